@@ -1,8 +1,14 @@
 package run
 
 import (
+	"slices"
+	"time"
+
 	"github.com/ettle/strcase"
+	"github.com/traefik/piceus/pkg/core"
 	"github.com/traefik/piceus/pkg/logger"
+	"github.com/traefik/piceus/pkg/meter"
+	"github.com/traefik/piceus/pkg/tracer"
 	"github.com/urfave/cli/v2"
 )
 
@@ -13,13 +19,53 @@ const (
 	flagPluginURL                 = "plugin-url"
 	flagGithubSearchQueries       = "github-search-queries"
 	flagGithubSearchQueriesIssues = "github-search-queries-issues"
+	flagGithubBaseURL             = "github-base-url"
+	flagGithubUploadURL           = "github-upload-url"
+	flagGithubModuleHost          = "github-module-host"
+	flagGoProxyURL                = "go-proxy-url"
+	flagReconcileMode             = "reconcile-mode"
+	flagReconcileMaxRatio         = "reconcile-max-ratio"
+	flagStore                     = "store"
+	flagStoreDir                  = "store-dir"
+	flagIssueTemplate             = "issue-template"
+	flagReadmeHTML                = "readme-html"
+	flagAssetsDir                 = "assets-dir"
+	flagAssetsURL                 = "assets-url"
+	flagConfigDecoding            = "config-decoding"
+
+	flagPluginToken            = "plugin-token"
+	flagPluginUsername         = "plugin-username"
+	flagPluginPassword         = "plugin-password"
+	flagPluginTimeout          = "plugin-timeout"
+	flagPluginRetryMax         = "plugin-retry-max"
+	flagPluginRetryWaitMin     = "plugin-retry-wait-min"
+	flagPluginRetryWaitMax     = "plugin-retry-wait-max"
+	flagPluginBreakerThreshold = "plugin-breaker-threshold"
+	flagPluginBreakerCooldown  = "plugin-breaker-cooldown"
+
+	flagDaemon                = "daemon"
+	flagDaemonAddr            = "daemon-addr"
+	flagScheduleInterval      = "schedule-interval"
+	flagScheduleCron          = "schedule-cron"
+	flagScheduleJitter        = "schedule-jitter"
+	flagDaemonShutdownTimeout = "daemon-shutdown-timeout"
+
+	flagAdminToken = "admin-token"
+
+	flagFeedDir        = "feed-dir"
+	flagFeedMaxEntries = "feed-max-entries"
+	flagFeedTitle      = "feed-title"
+	flagFeedLink       = "feed-link"
+	flagFeedURL        = "feed-url"
 
 	flagEnableMetrics   = "enable-metrics"
+	flagMetricsExporter = "metrics-exporter"
 	flagMetricsAddress  = "metrics-address"
 	flagMetricsInsecure = "metrics-insecure"
 	flagMetricsUsername = "metrics-username"
 	flagMetricsPassword = "metrics-password"
 
+	flagTracingExporter    = "tracing-exporter"
 	flagTracingAddress     = "tracing-address"
 	flagTracingInsecure    = "tracing-insecure"
 	flagTracingUsername    = "tracing-username"
@@ -33,70 +79,298 @@ func Command() *cli.Command {
 		Name:        "run",
 		Usage:       "Run Piceus",
 		Description: "Launch application piceus",
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:    flagLogLevel,
-				Usage:   "Log level",
-				EnvVars: []string{strcase.ToSNAKE(flagLogLevel)},
-				Value:   "info",
-			},
-			&cli.StringFlag{
-				Name:     flagGitHubToken,
-				Usage:    "GitHub Token.",
-				EnvVars:  []string{strcase.ToSNAKE(flagGitHubToken)},
-				Required: true,
-			},
-			&cli.BoolFlag{
-				Name:    flagDryRun,
-				Usage:   "Dry run mode.",
-				EnvVars: []string{strcase.ToSNAKE(flagDryRun)},
-				Value:   true,
-			},
-			&cli.StringFlag{
-				Name:     flagPluginURL,
-				Usage:    "Plugin Service URL",
-				EnvVars:  []string{strcase.ToSNAKE(flagPluginURL)},
-				Required: true,
-			},
-			// flagGithubSearchQueries queries used to search plugins on GitHub.
-			// https://help.github.com/en/github/searching-for-information-on-github/searching-for-repositories
-			&cli.StringSliceFlag{
-				Name:    flagGithubSearchQueries,
-				Usage:   "Github search queries",
-				EnvVars: []string{strcase.ToSNAKE(flagGithubSearchQueries)},
-				Value:   cli.NewStringSlice("topic:traefik-plugin language:Go archived:false is:public"),
-			},
-			// flagGithubSearchQueryIssues queries used to search issues opened by the bot account.
-			// https://help.github.com/en/github/searching-for-information-on-github/searching-for-repositories
-			&cli.StringSliceFlag{
-				Name:    flagGithubSearchQueriesIssues,
-				Usage:   "Github queries used to search issues opened by the bot account",
-				EnvVars: []string{strcase.ToSNAKE(flagGithubSearchQueriesIssues)},
-				Value:   cli.NewStringSlice("is:open is:issue is:public author:traefiker"),
-			},
-		},
+		Flags:       slices.Concat(getFlags(), getDaemonFlags(), getAdminFlags()),
 		Action: func(cliCtx *cli.Context) error {
 			logger.Setup(cliCtx.String(flagLogLevel))
 
 			cfg := buildConfig(cliCtx)
 
+			if cliCtx.Bool(flagDaemon) {
+				return runDaemon(cliCtx.Context, cfg, buildDaemonConfig(cliCtx))
+			}
+
 			return run(cliCtx.Context, cfg)
 		},
 	}
 
-	cmd.Flags = append(cmd.Flags, getMetricsFlags()...)
-	cmd.Flags = append(cmd.Flags, getTracingFlags()...)
-
 	return cmd
 }
 
+// getFlags returns the flags shared by the commands running the scrapper.
+func getFlags() []cli.Flag {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagLogLevel,
+			Usage:   "Log level",
+			EnvVars: []string{strcase.ToSNAKE(flagLogLevel)},
+			Value:   "info",
+		},
+		&cli.StringFlag{
+			Name:     flagGitHubToken,
+			Usage:    "GitHub Token.",
+			EnvVars:  []string{strcase.ToSNAKE(flagGitHubToken)},
+			Required: true,
+		},
+		&cli.BoolFlag{
+			Name:    flagDryRun,
+			Usage:   "Dry run mode.",
+			EnvVars: []string{strcase.ToSNAKE(flagDryRun)},
+			Value:   true,
+		},
+		&cli.StringFlag{
+			Name:    flagPluginURL,
+			Usage:   "Plugin Service URL (required by the service store)",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginURL)},
+		},
+		&cli.StringFlag{
+			Name:    flagStore,
+			Usage:   "Catalog storage backend (service, file)",
+			EnvVars: []string{strcase.ToSNAKE(flagStore)},
+			Value:   storeService,
+		},
+		&cli.StringFlag{
+			Name:    flagStoreDir,
+			Usage:   "Directory of the catalog (file store)",
+			EnvVars: []string{strcase.ToSNAKE(flagStoreDir)},
+			Value:   "catalog",
+		},
+		// flagGithubSearchQueries queries used to search plugins on GitHub.
+		// https://help.github.com/en/github/searching-for-information-on-github/searching-for-repositories
+		&cli.StringSliceFlag{
+			Name:    flagGithubSearchQueries,
+			Usage:   "Github search queries",
+			EnvVars: []string{strcase.ToSNAKE(flagGithubSearchQueries)},
+			Value:   cli.NewStringSlice("topic:traefik-plugin language:Go archived:false is:public"),
+		},
+		// flagGithubSearchQueryIssues queries used to search issues opened by the bot account.
+		// https://help.github.com/en/github/searching-for-information-on-github/searching-for-repositories
+		&cli.StringSliceFlag{
+			Name:    flagGithubSearchQueriesIssues,
+			Usage:   "Github queries used to search issues opened by the bot account",
+			EnvVars: []string{strcase.ToSNAKE(flagGithubSearchQueriesIssues)},
+			Value:   cli.NewStringSlice("is:open is:issue is:public author:traefiker"),
+		},
+		// flagGithubBaseURL and flagGithubUploadURL are used to target a GitHub Enterprise Server instance.
+		&cli.StringFlag{
+			Name:    flagGithubBaseURL,
+			Usage:   "GitHub Enterprise Server base URL (ex: https://github.example.com)",
+			EnvVars: []string{strcase.ToSNAKE(flagGithubBaseURL)},
+		},
+		&cli.StringFlag{
+			Name:    flagGithubUploadURL,
+			Usage:   "GitHub Enterprise Server upload URL (default to the base URL)",
+			EnvVars: []string{strcase.ToSNAKE(flagGithubUploadURL)},
+		},
+		&cli.StringFlag{
+			Name:    flagGithubModuleHost,
+			Usage:   "Host prefix of the plugin module names",
+			EnvVars: []string{strcase.ToSNAKE(flagGithubModuleHost)},
+			Value:   "github.com",
+		},
+		&cli.StringFlag{
+			Name:    flagGoProxyURL,
+			Usage:   "Go module proxy URL used to fetch the plugin sources (default to the public Go proxy)",
+			EnvVars: []string{strcase.ToSNAKE(flagGoProxyURL)},
+		},
+		&cli.StringFlag{
+			Name:    flagReconcileMode,
+			Usage:   "Reconciliation of the plugins that disappeared from the search results (none, deprecate, delete)",
+			EnvVars: []string{strcase.ToSNAKE(flagReconcileMode)},
+			Value:   core.ReconcileNone,
+		},
+		&cli.Float64Flag{
+			Name:    flagReconcileMaxRatio,
+			Usage:   "Maximum ratio of catalog plugins reconciled in a single run",
+			EnvVars: []string{strcase.ToSNAKE(flagReconcileMaxRatio)},
+			Value:   0.1,
+		},
+		&cli.StringFlag{
+			Name:    flagIssueTemplate,
+			Usage:   "Go template file of the analyzer issues body (default to the built-in template)",
+			EnvVars: []string{strcase.ToSNAKE(flagIssueTemplate)},
+		},
+		&cli.BoolFlag{
+			Name:    flagReadmeHTML,
+			Usage:   "Store a pre-rendered HTML version and a table of contents of the plugin READMEs",
+			EnvVars: []string{strcase.ToSNAKE(flagReadmeHTML)},
+		},
+		&cli.StringFlag{
+			Name:    flagAssetsDir,
+			Usage:   "Directory where the plugin icons and banners are mirrored (disabled if empty)",
+			EnvVars: []string{strcase.ToSNAKE(flagAssetsDir)},
+		},
+		&cli.StringFlag{
+			Name:    flagAssetsURL,
+			Usage:   "Base URL where the assets directory is served (required by the assets mirroring)",
+			EnvVars: []string{strcase.ToSNAKE(flagAssetsURL)},
+		},
+		&cli.StringFlag{
+			Name:    flagConfigDecoding,
+			Usage:   "Decoding of the plugin test data (mapstructure, traefik: also decoded from the labels and the Kubernetes CRDs)",
+			EnvVars: []string{strcase.ToSNAKE(flagConfigDecoding)},
+			Value:   core.DecodingMapstructure,
+		},
+	}
+
+	flags = append(flags, getPluginFlags()...)
+	flags = append(flags, getFeedFlags()...)
+	flags = append(flags, getMetricsFlags()...)
+	flags = append(flags, getTracingFlags()...)
+
+	return flags
+}
+
+func getPluginFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagPluginToken,
+			Usage:   "Bearer token to connect to the Plugin Service",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginToken)},
+		},
+		&cli.StringFlag{
+			Name:    flagPluginUsername,
+			Usage:   "Username to connect to the Plugin Service (basic auth)",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginUsername)},
+		},
+		&cli.StringFlag{
+			Name:    flagPluginPassword,
+			Usage:   "Password to connect to the Plugin Service (basic auth)",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginPassword)},
+		},
+		&cli.DurationFlag{
+			Name:    flagPluginTimeout,
+			Usage:   "Timeout of the Plugin Service requests",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginTimeout)},
+			Value:   10 * time.Second,
+		},
+		&cli.IntFlag{
+			Name:    flagPluginRetryMax,
+			Usage:   "Maximum number of retries of the idempotent Plugin Service requests",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginRetryMax)},
+			Value:   3,
+		},
+		&cli.DurationFlag{
+			Name:    flagPluginRetryWaitMin,
+			Usage:   "Minimum time to wait before retrying a Plugin Service request",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginRetryWaitMin)},
+			Value:   time.Second,
+		},
+		&cli.DurationFlag{
+			Name:    flagPluginRetryWaitMax,
+			Usage:   "Maximum time to wait before retrying a Plugin Service request",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginRetryWaitMax)},
+			Value:   10 * time.Second,
+		},
+		&cli.IntFlag{
+			Name:    flagPluginBreakerThreshold,
+			Usage:   "Number of consecutive Plugin Service failures before aborting the run (0 to disable)",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginBreakerThreshold)},
+			Value:   5,
+		},
+		&cli.DurationFlag{
+			Name:    flagPluginBreakerCooldown,
+			Usage:   "Time to wait before calling the Plugin Service again after too many failures",
+			EnvVars: []string{strcase.ToSNAKE(flagPluginBreakerCooldown)},
+			Value:   time.Minute,
+		},
+	}
+}
+
+func getDaemonFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    flagDaemon,
+			Usage:   "Run periodically instead of once",
+			EnvVars: []string{strcase.ToSNAKE(flagDaemon)},
+		},
+		&cli.StringFlag{
+			Name:    flagDaemonAddr,
+			Usage:   "Address of the health, readiness and status endpoints (daemon mode)",
+			EnvVars: []string{strcase.ToSNAKE(flagDaemonAddr)},
+			Value:   ":8080",
+		},
+		&cli.DurationFlag{
+			Name:    flagScheduleInterval,
+			Usage:   "Interval between two runs (daemon mode)",
+			EnvVars: []string{strcase.ToSNAKE(flagScheduleInterval)},
+			Value:   time.Hour,
+		},
+		&cli.StringFlag{
+			Name:    flagScheduleCron,
+			Usage:   "Cron expression of the runs, replaces the interval (daemon mode)",
+			EnvVars: []string{strcase.ToSNAKE(flagScheduleCron)},
+		},
+		&cli.DurationFlag{
+			Name:    flagScheduleJitter,
+			Usage:   "Maximum random delay before the first run (daemon mode)",
+			EnvVars: []string{strcase.ToSNAKE(flagScheduleJitter)},
+			Value:   time.Minute,
+		},
+		&cli.DurationFlag{
+			Name:    flagDaemonShutdownTimeout,
+			Usage:   "Maximum time to wait for the current run on shutdown (daemon mode)",
+			EnvVars: []string{strcase.ToSNAKE(flagDaemonShutdownTimeout)},
+			Value:   5 * time.Minute,
+		},
+	}
+}
+
+func getAdminFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagAdminToken,
+			Usage:   "Bearer token of the admin API (disabled if empty, daemon and serve modes)",
+			EnvVars: []string{strcase.ToSNAKE(flagAdminToken)},
+		},
+	}
+}
+
+func getFeedFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagFeedDir,
+			Usage:   "Directory of the feeds of the added and updated plugins (disabled if empty)",
+			EnvVars: []string{strcase.ToSNAKE(flagFeedDir)},
+		},
+		&cli.IntFlag{
+			Name:    flagFeedMaxEntries,
+			Usage:   "Maximum number of entries of the feeds",
+			EnvVars: []string{strcase.ToSNAKE(flagFeedMaxEntries)},
+			Value:   50,
+		},
+		&cli.StringFlag{
+			Name:    flagFeedTitle,
+			Usage:   "Title of the feeds",
+			EnvVars: []string{strcase.ToSNAKE(flagFeedTitle)},
+			Value:   "Traefik Plugin Catalog",
+		},
+		&cli.StringFlag{
+			Name:    flagFeedLink,
+			Usage:   "URL of the plugin catalog",
+			EnvVars: []string{strcase.ToSNAKE(flagFeedLink)},
+			Value:   "https://plugins.traefik.io",
+		},
+		&cli.StringFlag{
+			Name:    flagFeedURL,
+			Usage:   "Public URL of the feeds directory",
+			EnvVars: []string{strcase.ToSNAKE(flagFeedURL)},
+			Value:   "https://plugins.traefik.io/feeds",
+		},
+	}
+}
+
 func getMetricsFlags() []cli.Flag {
 	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagMetricsExporter,
+			Usage:   "Metrics exporter: otlp-http, otlp-grpc, stdout, prometheus (/metrics endpoint, daemon and serve modes) or none",
+			EnvVars: []string{strcase.ToSNAKE(flagMetricsExporter)},
+			Value:   meter.ExporterOTLPHTTP,
+		},
 		&cli.StringFlag{
 			Name:    flagMetricsAddress,
-			Usage:   "Address to send metrics",
+			Usage:   "Address to send metrics (OTLP exporters) (default: \"" + defaultMetricsHost + ":4318\", or \":4317\" with otlp-grpc)",
 			EnvVars: []string{strcase.ToSNAKE(flagMetricsAddress)},
-			Value:   "otel-collector.observability.svc.cluster.local:4318",
 		},
 		&cli.BoolFlag{
 			Name:    flagEnableMetrics,
@@ -127,11 +401,16 @@ func getMetricsFlags() []cli.Flag {
 
 func getTracingFlags() []cli.Flag {
 	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagTracingExporter,
+			Usage:   "Tracing exporter: otlp-http, otlp-grpc, stdout or none",
+			EnvVars: []string{strcase.ToSNAKE(flagTracingExporter)},
+			Value:   tracer.ExporterOTLPHTTP,
+		},
 		&cli.StringFlag{
 			Name:    flagTracingAddress,
-			Usage:   "Address to send traces",
+			Usage:   "Address to send traces (OTLP exporters) (default: \"" + defaultTracingHost + ":4318\", or \":4317\" with otlp-grpc)",
 			EnvVars: []string{strcase.ToSNAKE(flagTracingAddress)},
-			Value:   "jaeger.jaeger.svc.cluster.local:4318",
 		},
 		&cli.BoolFlag{
 			Name:    flagTracingInsecure,
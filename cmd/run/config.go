@@ -1,43 +1,168 @@
 package run
 
 import (
+	"time"
+
+	"github.com/traefik/piceus/pkg/daemon"
 	"github.com/traefik/piceus/pkg/meter"
 	"github.com/traefik/piceus/pkg/tracer"
 	"github.com/urfave/cli/v2"
 )
 
+// Default hosts of the OTLP exporters.
+const (
+	defaultMetricsHost = "otel-collector.observability.svc.cluster.local"
+	defaultTracingHost = "jaeger.jaeger.svc.cluster.local"
+)
+
 // Config represents the configuration for the run command.
 type Config struct {
 	GithubToken string
 	PluginURL   string
 
+	Store    string
+	StoreDir string
+
+	Plugin PluginConfig
+
 	DryRun bool
 
 	GithubSearchQueries       []string
 	GithubSearchQueriesIssues []string
 
+	GithubBaseURL    string
+	GithubUploadURL  string
+	GithubModuleHost string
+
+	GoProxyURL string
+
+	ReconcileMode     string
+	ReconcileMaxRatio float64
+
+	IssueTemplate string
+	ReadmeHTML    bool
+
+	AssetsDir string
+	AssetsURL string
+
+	ConfigDecoding string
+
+	Feed FeedConfig
+
 	EnableMetrics bool
 	Metrics       meter.Config
 	Tracing       tracer.Config
 }
 
+// PluginConfig represents the configuration of the Plugin Service client.
+type PluginConfig struct {
+	Token            string
+	Username         string
+	Password         string
+	Timeout          time.Duration
+	RetryMax         int
+	RetryWaitMin     time.Duration
+	RetryWaitMax     time.Duration
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// FeedConfig represents the configuration of the feeds.
+type FeedConfig struct {
+	Dir        string
+	MaxEntries int
+	Title      string
+	Link       string
+	URL        string
+}
+
+// DaemonConfig represents the configuration of the daemon mode.
+type DaemonConfig struct {
+	Addr       string
+	AdminToken string
+	Schedule   daemon.Config
+}
+
+func buildDaemonConfig(cliCtx *cli.Context) DaemonConfig {
+	return DaemonConfig{
+		Addr:       cliCtx.String(flagDaemonAddr),
+		AdminToken: cliCtx.String(flagAdminToken),
+		Schedule: daemon.Config{
+			Interval:        cliCtx.Duration(flagScheduleInterval),
+			Cron:            cliCtx.String(flagScheduleCron),
+			Jitter:          cliCtx.Duration(flagScheduleJitter),
+			ShutdownTimeout: cliCtx.Duration(flagDaemonShutdownTimeout),
+		},
+	}
+}
+
+// ServeConfig represents the configuration of the serve command.
+type ServeConfig struct {
+	Addr          string
+	WebhookSecret string
+	QueueSize     int
+	AdminToken    string
+}
+
+func buildServeConfig(cliCtx *cli.Context) ServeConfig {
+	return ServeConfig{
+		Addr:          cliCtx.String(flagServeAddr),
+		WebhookSecret: cliCtx.String(flagWebhookSecret),
+		QueueSize:     cliCtx.Int(flagWebhookQueueSize),
+		AdminToken:    cliCtx.String(flagAdminToken),
+	}
+}
+
 func buildConfig(cliCtx *cli.Context) Config {
 	return Config{
 		GithubToken:               cliCtx.String(flagGitHubToken),
 		PluginURL:                 cliCtx.String(flagPluginURL),
+		Store:                     cliCtx.String(flagStore),
+		StoreDir:                  cliCtx.String(flagStoreDir),
 		DryRun:                    cliCtx.Bool(flagDryRun),
 		GithubSearchQueries:       cliCtx.StringSlice(flagGithubSearchQueries),
 		GithubSearchQueriesIssues: cliCtx.StringSlice(flagGithubSearchQueriesIssues),
+		GithubBaseURL:             cliCtx.String(flagGithubBaseURL),
+		GithubUploadURL:           cliCtx.String(flagGithubUploadURL),
+		GithubModuleHost:          cliCtx.String(flagGithubModuleHost),
+		GoProxyURL:                cliCtx.String(flagGoProxyURL),
+		ReconcileMode:             cliCtx.String(flagReconcileMode),
+		ReconcileMaxRatio:         cliCtx.Float64(flagReconcileMaxRatio),
+		IssueTemplate:             cliCtx.String(flagIssueTemplate),
+		ReadmeHTML:                cliCtx.Bool(flagReadmeHTML),
+		AssetsDir:                 cliCtx.String(flagAssetsDir),
+		AssetsURL:                 cliCtx.String(flagAssetsURL),
+		ConfigDecoding:            cliCtx.String(flagConfigDecoding),
 		EnableMetrics:             cliCtx.Bool(flagEnableMetrics),
+		Plugin: PluginConfig{
+			Token:            cliCtx.String(flagPluginToken),
+			Username:         cliCtx.String(flagPluginUsername),
+			Password:         cliCtx.String(flagPluginPassword),
+			Timeout:          cliCtx.Duration(flagPluginTimeout),
+			RetryMax:         cliCtx.Int(flagPluginRetryMax),
+			RetryWaitMin:     cliCtx.Duration(flagPluginRetryWaitMin),
+			RetryWaitMax:     cliCtx.Duration(flagPluginRetryWaitMax),
+			BreakerThreshold: cliCtx.Int(flagPluginBreakerThreshold),
+			BreakerCooldown:  cliCtx.Duration(flagPluginBreakerCooldown),
+		},
+		Feed: FeedConfig{
+			Dir:        cliCtx.String(flagFeedDir),
+			MaxEntries: cliCtx.Int(flagFeedMaxEntries),
+			Title:      cliCtx.String(flagFeedTitle),
+			Link:       cliCtx.String(flagFeedLink),
+			URL:        cliCtx.String(flagFeedURL),
+		},
 		Metrics: meter.Config{
-			Address:     cliCtx.String(flagMetricsAddress),
+			Exporter:    cliCtx.String(flagMetricsExporter),
+			Address:     otlpAddress(cliCtx.String(flagMetricsAddress), cliCtx.String(flagMetricsExporter), defaultMetricsHost),
 			Insecure:    cliCtx.Bool(flagMetricsInsecure),
 			Username:    cliCtx.String(flagMetricsUsername),
 			Password:    cliCtx.String(flagMetricsPassword),
 			ServiceName: "piceus",
 		},
 		Tracing: tracer.Config{
-			Address:     cliCtx.String(flagTracingAddress),
+			Exporter:    cliCtx.String(flagTracingExporter),
+			Address:     otlpAddress(cliCtx.String(flagTracingAddress), cliCtx.String(flagTracingExporter), defaultTracingHost),
 			Insecure:    cliCtx.Bool(flagTracingInsecure),
 			Username:    cliCtx.String(flagTracingUsername),
 			Password:    cliCtx.String(flagTracingPassword),
@@ -46,3 +171,18 @@ func buildConfig(cliCtx *cli.Context) Config {
 		},
 	}
 }
+
+// otlpAddress returns the address of an OTLP exporter:
+// the default port is 4317 for OTLP over gRPC, and 4318 for OTLP over HTTP.
+func otlpAddress(address, exporter, defaultHost string) string {
+	if address != "" {
+		return address
+	}
+
+	// The metrics and tracing exporters have the same names.
+	if exporter == meter.ExporterOTLPGRPC {
+		return defaultHost + ":4317"
+	}
+
+	return defaultHost + ":4318"
+}
@@ -2,15 +2,24 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/ldez/grignotin/goproxy"
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/internal/filestore"
 	"github.com/traefik/piceus/internal/plugin"
 	"github.com/traefik/piceus/pkg/client"
 	"github.com/traefik/piceus/pkg/core"
+	"github.com/traefik/piceus/pkg/daemon"
+	"github.com/traefik/piceus/pkg/feed"
 	"github.com/traefik/piceus/pkg/meter"
 	"github.com/traefik/piceus/pkg/sources"
 	"github.com/traefik/piceus/pkg/tracer"
@@ -18,33 +27,124 @@ import (
 	"go.opentelemetry.io/otel/propagation"
 )
 
+const (
+	storeService = "service"
+	storeFile    = "file"
+)
+
 func run(ctx context.Context, cfg Config) error {
+	app, err := setup(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer app.close()
+
+	stats, err := app.Run(ctx)
+
+	log.Ctx(ctx).Info().Interface("stats", stats).Msg("Run completed")
+
+	return err
+}
+
+func runDaemon(ctx context.Context, cfg Config, daemonCfg DaemonConfig) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app, err := setup(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer app.close()
+
+	d, err := daemon.New(daemonCfg.Schedule, app.Run)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", d.Handler())
+	app.registerAdmin(ctx, mux, daemonCfg.AdminToken)
+	app.registerMetrics(mux)
+
+	return listenAndServe(ctx, daemonCfg.Addr, mux, d.Run)
+}
+
+// application the scrapper and its dependencies.
+type application struct {
+	scrapper *core.Scrapper
+	events   *feed.Log
+	feedCfg  FeedConfig
+
+	// busy serializes the runs and the analyses of a single repository (daemon, webhooks, admin API).
+	busy chan struct{}
+
+	// metricsHandler the Prometheus /metrics handler (nil if disabled).
+	metricsHandler http.Handler
+
+	teardown []func()
+}
+
+func setup(ctx context.Context, cfg Config) (*application, error) {
+	app := &application{feedCfg: cfg.Feed, busy: make(chan struct{}, 1)}
+
 	stopTracer, err := setupTracing(ctx, cfg.Tracing)
 	if err != nil {
-		return fmt.Errorf("setting up tracing provider: %w", err)
+		return nil, fmt.Errorf("setting up tracing provider: %w", err)
 	}
-	defer stopTracer()
+	app.teardown = append(app.teardown, stopTracer)
 
 	if cfg.EnableMetrics {
-		stopMeter, mErr := setupMetrics(ctx, cfg.Metrics)
+		metricsHandler, stopMeter, mErr := setupMetrics(ctx, cfg.Metrics)
 		if mErr != nil {
-			return fmt.Errorf("setting up metrics provider: %w", mErr)
+			app.close()
+			return nil, fmt.Errorf("setting up metrics provider: %w", mErr)
 		}
-		defer stopMeter()
+		app.teardown = append(app.teardown, stopMeter)
+		app.metricsHandler = metricsHandler
 	}
 
-	ghClient, err := client.New(ctx,
+	app.scrapper, err = app.newScrapper(ctx, cfg)
+	if err != nil {
+		app.close()
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (a *application) newScrapper(ctx context.Context, cfg Config) (*core.Scrapper, error) {
+	ghOptions := []client.Option{
 		client.WithToken(cfg.GithubToken),
 		client.WithMetrics(cfg.EnableMetrics),
 		client.WithRateLimiter(30, 25, time.Now().Add(time.Minute)),
 		client.WithRetry(4, 30*time.Second),
-	)
+	}
+
+	if cfg.GithubBaseURL != "" {
+		ghOptions = append(ghOptions, client.WithEnterpriseURLs(cfg.GithubBaseURL, cfg.GithubUploadURL))
+	}
+
+	ghClient, err := client.New(ctx, ghOptions...)
 	if err != nil {
-		return fmt.Errorf("creating github client: %w", err)
+		return nil, fmt.Errorf("creating github client: %w", err)
+	}
+
+	if err = ghClient.SyncRateLimits(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("Unable to initialize rate limits, using default values")
 	}
-	gpClient := goproxy.NewClient("")
 
-	pgClient := plugin.New(cfg.PluginURL)
+	a.teardown = append(a.teardown, func() {
+		log.Ctx(ctx).Info().Int64("retries", ghClient.Retries()).Msg("GitHub API retries")
+	})
+
+	gpClient := goproxy.NewClient(cfg.GoProxyURL)
+
+	pgClient, err := newPluginClient(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	var srcs core.Sources
 	if _, ok := os.LookupEnv(core.PrivateModeEnv); ok {
@@ -53,20 +153,184 @@ func run(ctx context.Context, cfg Config) error {
 		srcs = &sources.GoProxy{Client: gpClient}
 	}
 
-	scrapper := core.NewScrapper(ghClient.GithubClient(), gpClient, pgClient, cfg.DryRun, srcs, cfg.GithubSearchQueries, cfg.GithubSearchQueriesIssues)
+	switch cfg.ReconcileMode {
+	case "", core.ReconcileNone, core.ReconcileDeprecate, core.ReconcileDelete:
+	default:
+		return nil, fmt.Errorf("unsupported reconcile mode: %s", cfg.ReconcileMode)
+	}
+
+	scrapperOptions := []core.Option{
+		core.WithModuleHost(cfg.GithubModuleHost),
+		core.WithReconciliation(cfg.ReconcileMode, cfg.ReconcileMaxRatio),
+		core.WithReadmeHTML(cfg.ReadmeHTML),
+	}
+
+	switch cfg.ConfigDecoding {
+	case "", core.DecodingMapstructure, core.DecodingTraefik:
+		scrapperOptions = append(scrapperOptions, core.WithConfigDecoding(cfg.ConfigDecoding))
+	default:
+		return nil, fmt.Errorf("unsupported config decoding mode: %s", cfg.ConfigDecoding)
+	}
+
+	if cfg.Feed.Dir != "" {
+		a.events, err = feed.NewLog(filepath.Join(cfg.Feed.Dir, "events.json"), cfg.Feed.MaxEntries)
+		if err != nil {
+			return nil, fmt.Errorf("creating feed event log: %w", err)
+		}
+
+		scrapperOptions = append(scrapperOptions, core.WithEventRecorder(a.events))
+	}
+
+	if cfg.IssueTemplate != "" {
+		tmpl, err := core.ParseIssueTemplate(cfg.IssueTemplate)
+		if err != nil {
+			return nil, err
+		}
+
+		scrapperOptions = append(scrapperOptions, core.WithIssueTemplate(tmpl))
+	}
+
+	if cfg.AssetsDir != "" {
+		if cfg.AssetsURL == "" {
+			return nil, errors.New("the assets URL is required by the assets mirroring")
+		}
+
+		assetsURL, err := url.Parse(cfg.AssetsURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing assets URL: %w", err)
+		}
+
+		scrapperOptions = append(scrapperOptions, core.WithAssetMirror(cfg.AssetsDir, assetsURL))
+	}
+
+	return core.NewScrapper(ghClient.GithubClient(), gpClient, pgClient, cfg.DryRun, srcs, cfg.GithubSearchQueries, cfg.GithubSearchQueriesIssues, scrapperOptions...), nil
+}
 
-	return scrapper.Run(ctx)
+// writeFeeds writes the feeds (if enabled).
+func (a *application) writeFeeds(ctx context.Context) {
+	if a.events == nil {
+		return
+	}
+
+	meta := feed.Metadata{Title: a.feedCfg.Title, Link: a.feedCfg.Link, FeedURL: a.feedCfg.URL}
+	if err := a.events.WriteFeeds(a.feedCfg.Dir, meta); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to write the feeds")
+	}
 }
 
-func setupMetrics(ctx context.Context, cfg meter.Config) (func(), error) {
-	metricProvider, err := meter.NewOTLPProvider(ctx, cfg)
+// lock waits for the current run or analysis to complete, and returns the function releasing the lock.
+func (a *application) lock(ctx context.Context) (func(), error) {
+	select {
+	case a.busy <- struct{}{}:
+		return func() { <-a.busy }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the current analysis: %w", ctx.Err())
+	}
+}
+
+// tryLock acquires the lock without waiting, it returns false if a run or an analysis is in progress.
+func (a *application) tryLock() (func(), bool) {
+	select {
+	case a.busy <- struct{}{}:
+		return func() { <-a.busy }, true
+	default:
+		return nil, false
+	}
+}
+
+// Run analyzes all the repositories, then writes the feeds.
+func (a *application) Run(ctx context.Context) (core.Stats, error) {
+	unlock, err := a.lock(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("setup metrics provider: %w", err)
+		return core.Stats{}, err
+	}
+	defer unlock()
+
+	defer a.writeFeeds(ctx)
+
+	return a.scrapper.Run(ctx)
+}
+
+// RunRepository analyzes a single repository, then writes the feeds.
+func (a *application) RunRepository(ctx context.Context, owner, name string) (core.Result, error) {
+	unlock, err := a.lock(ctx)
+	if err != nil {
+		return core.Result{}, err
+	}
+	defer unlock()
+
+	defer a.writeFeeds(ctx)
+
+	return a.scrapper.RunRepository(ctx, owner, name)
+}
+
+// ReconcileRepository reconciles the catalog entries of a repository which cannot be analyzed anymore.
+func (a *application) ReconcileRepository(ctx context.Context, owner, name string) (core.Result, error) {
+	unlock, err := a.lock(ctx)
+	if err != nil {
+		return core.Result{}, err
+	}
+	defer unlock()
+
+	return a.scrapper.ReconcileRepository(ctx, owner, name)
+}
+
+// Result returns the result of the last analysis of a repository.
+func (a *application) Result(owner, name string) (core.Result, bool) {
+	return a.scrapper.Result(owner, name)
+}
+
+// Block adds a repository to the blocklist.
+func (a *application) Block(fullName string) {
+	a.scrapper.Block(fullName)
+}
+
+// close releases the resources in the reverse order of their creation.
+func (a *application) close() {
+	for i := len(a.teardown) - 1; i >= 0; i-- {
+		a.teardown[i]()
+	}
+}
+
+func newPluginClient(cfg Config) (core.PluginClient, error) {
+	switch cfg.Store {
+	case storeService:
+		if cfg.PluginURL == "" {
+			return nil, errors.New("the plugin service URL is required by the service store")
+		}
+
+		return plugin.New(cfg.PluginURL,
+			plugin.WithBearerToken(cfg.Plugin.Token),
+			plugin.WithBasicAuth(cfg.Plugin.Username, cfg.Plugin.Password),
+			plugin.WithTimeout(cfg.Plugin.Timeout),
+			plugin.WithRetry(cfg.Plugin.RetryMax, cfg.Plugin.RetryWaitMin, cfg.Plugin.RetryWaitMax),
+			plugin.WithCircuitBreaker(cfg.Plugin.BreakerThreshold, cfg.Plugin.BreakerCooldown),
+		), nil
+
+	case storeFile:
+		store, err := filestore.New(cfg.StoreDir)
+		if err != nil {
+			return nil, fmt.Errorf("creating file store: %w", err)
+		}
+
+		return store, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported store: %s", cfg.Store)
+	}
+}
+
+// setupMetrics sets the global meter provider.
+// It returns the Prometheus /metrics handler, or nil if the exporter is not prometheus.
+func setupMetrics(ctx context.Context, cfg meter.Config) (http.Handler, func(), error) {
+	metricProvider, err := meter.NewProvider(ctx, cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("setup metrics provider: %w", err)
 	}
 
 	otel.SetMeterProvider(metricProvider)
 
-	return func() {
+	return metricProvider.Handler(), func() {
 		if err := metricProvider.Stop(ctx); err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("Stopping metrics provider")
 		}
@@ -75,7 +339,7 @@ func setupMetrics(ctx context.Context, cfg meter.Config) (func(), error) {
 
 func setupTracing(ctx context.Context, cfg tracer.Config) (func(), error) {
 	tracePropagator := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{})
-	traceProvider, err := tracer.NewOTLPProvider(ctx, cfg)
+	traceProvider, err := tracer.NewProvider(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("setup tracing provider: %w", err)
 	}
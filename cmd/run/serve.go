@@ -0,0 +1,171 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/ettle/strcase"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/pkg/admin"
+	"github.com/traefik/piceus/pkg/core"
+	"github.com/traefik/piceus/pkg/logger"
+	"github.com/traefik/piceus/pkg/webhook"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagServeAddr        = "addr"
+	flagWebhookSecret    = "webhook-secret"
+	flagWebhookQueueSize = "webhook-queue-size"
+)
+
+// ServeCommand creates the serve command.
+func ServeCommand() *cli.Command {
+	cmd := &cli.Command{
+		Name:        "serve",
+		Usage:       "Serve Piceus",
+		Description: "Analyze the repositories when receiving GitHub webhooks",
+		Flags:       slices.Concat(getFlags(), getServeFlags(), getAdminFlags()),
+		Action: func(cliCtx *cli.Context) error {
+			logger.Setup(cliCtx.String(flagLogLevel))
+
+			cfg := buildConfig(cliCtx)
+
+			return serve(cliCtx.Context, cfg, buildServeConfig(cliCtx))
+		},
+	}
+
+	return cmd
+}
+
+func getServeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    flagServeAddr,
+			Usage:   "Address to listen on",
+			EnvVars: []string{strcase.ToSNAKE(flagServeAddr)},
+			Value:   ":8080",
+		},
+		&cli.StringFlag{
+			Name:     flagWebhookSecret,
+			Usage:    "Secret used to verify the GitHub webhooks signature (X-Hub-Signature-256)",
+			EnvVars:  []string{strcase.ToSNAKE(flagWebhookSecret)},
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:    flagWebhookQueueSize,
+			Usage:   "Maximum number of repositories waiting to be analyzed",
+			EnvVars: []string{strcase.ToSNAKE(flagWebhookQueueSize)},
+			Value:   100,
+		},
+	}
+}
+
+func serve(ctx context.Context, cfg Config, serveCfg ServeConfig) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	app, err := setup(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	defer app.close()
+
+	queue := webhook.NewQueue(app, serveCfg.QueueSize)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /webhook", webhook.NewHandler(serveCfg.WebhookSecret, queue))
+	app.registerAdmin(ctx, mux, serveCfg.AdminToken)
+	app.registerMetrics(mux)
+
+	return listenAndServe(ctx, serveCfg.Addr, mux, queue.Run)
+}
+
+// registerAdmin mounts the admin API on the mux, the admin API is disabled if the token is empty.
+func (a *application) registerAdmin(ctx context.Context, mux *http.ServeMux, token string) {
+	if token == "" {
+		log.Ctx(ctx).Info().Msg("Admin API disabled")
+		return
+	}
+
+	handler := admin.NewHandler(token, adminScrapper{application: a})
+
+	mux.Handle("POST /analyze/", handler)
+	mux.Handle("GET /repos/", handler)
+	mux.Handle("POST /blocklist", handler)
+}
+
+// adminScrapper the scrapper of the admin API:
+// an analysis is rejected while a run or another analysis is in progress, instead of holding the request until its end.
+type adminScrapper struct {
+	*application
+}
+
+// RunRepository analyzes a single repository if nothing else is in progress, then writes the feeds.
+func (a adminScrapper) RunRepository(ctx context.Context, owner, name string) (core.Result, error) {
+	unlock, ok := a.tryLock()
+	if !ok {
+		return core.Result{}, admin.ErrBusy
+	}
+	defer unlock()
+
+	defer a.writeFeeds(ctx)
+
+	return a.scrapper.RunRepository(ctx, owner, name)
+}
+
+// registerMetrics mounts the Prometheus /metrics endpoint on the mux, if the prometheus exporter is enabled.
+func (a *application) registerMetrics(mux *http.ServeMux) {
+	if a.metricsHandler == nil {
+		return
+	}
+
+	mux.Handle("GET /metrics", a.metricsHandler)
+}
+
+// listenAndServe serves the handler and runs the background task until the context is canceled.
+// The server is gracefully shut down, then the background task is awaited.
+func listenAndServe(ctx context.Context, addr string, handler http.Handler, background func(ctx context.Context)) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		background(ctx)
+	}()
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("Failed to shut down the server")
+		}
+	}()
+
+	log.Ctx(ctx).Info().Str("addr", addr).Msg("Server started")
+
+	err := server.ListenAndServe()
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	<-done
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package fakegithub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// zipDir creates a zip archive of a directory, the files are stored under the root directory (if not empty).
+func zipDir(dir, root string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := zip.NewWriter(&buf)
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		w, err := writer.Create(path.Join(root, filepath.ToSlash(rel)))
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(content)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive of %s: %w", dir, err)
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
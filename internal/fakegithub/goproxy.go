@@ -0,0 +1,96 @@
+package fakegithub
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+)
+
+// goProxy serves the Go module proxy protocol for the modules of the repositories tags.
+//
+//	/goproxy/<module>/@v/list
+//	/goproxy/<module>/@v/<version>.info
+//	/goproxy/<module>/@v/<version>.mod
+//	/goproxy/<module>/@v/<version>.zip
+func (s *Server) goProxy(rw http.ResponseWriter, req *http.Request) {
+	escapedPath, file, ok := strings.Cut(req.PathValue("path"), "/@v/")
+	if !ok {
+		http.NotFound(rw, req)
+		return
+	}
+
+	modPath, err := module.UnescapePath(escapedPath)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	repo, ok := s.modules[modPath]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(rw, "not found: "+modPath, http.StatusNotFound)
+		return
+	}
+
+	if file == "list" {
+		var versions []string
+		for _, tag := range repo.Tags {
+			versions = append(versions, tag.Name)
+		}
+
+		_, _ = rw.Write([]byte(strings.Join(versions, "\n")))
+		return
+	}
+
+	ext := filepath.Ext(file)
+	version := strings.TrimSuffix(file, ext)
+
+	var dir string
+	for _, tag := range repo.Tags {
+		if tag.Name == version {
+			dir = tag.Dir
+			break
+		}
+	}
+
+	if dir == "" {
+		http.Error(rw, "unknown revision "+version, http.StatusNotFound)
+		return
+	}
+
+	switch ext {
+	case ".info":
+		writeJSON(rw, http.StatusOK, map[string]interface{}{"Version": version, "Time": time.Time{}})
+
+	case ".mod":
+		content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = rw.Write(content)
+
+	case ".zip":
+		var buf bytes.Buffer
+		err := zip.CreateFromDir(&buf, module.Version{Path: modPath, Version: version}, dir)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		rw.Header().Set("Content-Type", "application/zip")
+		_, _ = rw.Write(buf.Bytes())
+
+	default:
+		http.NotFound(rw, req)
+	}
+}
@@ -0,0 +1,485 @@
+// Package fakegithub provides a fake GitHub API server and a fake Go module proxy backed by fixture directories.
+package fakegithub
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"golang.org/x/mod/modfile"
+)
+
+// Tag a repository tag, its sources are the files of the fixture directory.
+type Tag struct {
+	Name string
+	Dir  string
+}
+
+// Release the latest release of a repository, its zip asset is built from the fixture directory.
+type Release struct {
+	TagName   string
+	AssetName string
+	Dir       string
+}
+
+// Repository a fake repository.
+type Repository struct {
+	Owner    string
+	Name     string
+	Topics   []string
+	Stars    int
+	Archived bool
+	// Tags sorted from the newest to the oldest.
+	Tags    []Tag
+	Release *Release
+}
+
+func (r Repository) fullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+// Server a fake GitHub API server.
+type Server struct {
+	server *httptest.Server
+
+	mu            sync.Mutex
+	repositories  map[string]Repository
+	issues        []*github.Issue
+	createdIssues []*github.Issue
+
+	// modules maps module paths to repositories.
+	modules map[string]Repository
+}
+
+// NewServer creates and starts a fake GitHub API server.
+// The caller must call Close when finished.
+func NewServer(repositories ...Repository) (*Server, error) {
+	s := &Server{
+		repositories: make(map[string]Repository),
+		modules:      make(map[string]Repository),
+	}
+
+	for _, repo := range repositories {
+		s.repositories[repo.fullName()] = repo
+
+		for _, tag := range repo.Tags {
+			content, err := os.ReadFile(filepath.Join(tag.Dir, "go.mod"))
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read go.mod: %w", err)
+			}
+
+			modPath := modfile.ModulePath(content)
+			if modPath != "" {
+				s.modules[modPath] = repo
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search/repositories", s.searchRepositories)
+	mux.HandleFunc("GET /search/issues", s.searchIssues)
+	mux.HandleFunc("GET /repos/{owner}/{repo}", s.getRepository)
+	mux.HandleFunc("POST /repos/{owner}/{repo}/issues", s.createIssue)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/tags", s.listTags)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/contents/{path...}", s.getContents)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/readme", s.getReadme)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases/latest", s.getLatestRelease)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/releases/assets/{id}", s.downloadAsset)
+	mux.HandleFunc("GET /repos/{owner}/{repo}/zipball/{ref}", s.getZipballLink)
+	mux.HandleFunc("GET /archives/{owner}/{repo}/{ref}", s.downloadZipball)
+	mux.HandleFunc("GET /goproxy/{path...}", s.goProxy)
+
+	s.server = httptest.NewServer(mux)
+
+	return s, nil
+}
+
+// URL returns the base URL of the GitHub API.
+func (s *Server) URL() string {
+	return s.server.URL + "/"
+}
+
+// GoProxyURL returns the URL of the Go module proxy.
+func (s *Server) GoProxyURL() string {
+	return s.server.URL + "/goproxy"
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// AddIssue adds an existing open issue to a repository.
+func (s *Server) AddIssue(owner, repo, title string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.issues = append(s.issues, s.newIssue(owner, repo, title, ""))
+}
+
+// CloseIssues closes all the issues of a repository.
+func (s *Server) CloseIssues(owner, repo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repoURL := s.URL() + path.Join("repos", owner, repo)
+
+	for _, issue := range s.issues {
+		if issue.GetRepositoryURL() == repoURL {
+			issue.State = github.String("closed")
+		}
+	}
+}
+
+// CreatedIssues returns the issues created through the API.
+func (s *Server) CreatedIssues() []*github.Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.createdIssues)
+}
+
+func (s *Server) newIssue(owner, repo, title, body string) *github.Issue {
+	number := len(s.issues) + 1
+
+	return &github.Issue{
+		Number:        github.Int(number),
+		State:         github.String("open"),
+		Title:         github.String(title),
+		Body:          github.String(body),
+		RepositoryURL: github.String(s.URL() + path.Join("repos", owner, repo)),
+		HTMLURL:       github.String(fmt.Sprintf("%s/%s/%s/issues/%d", s.server.URL, owner, repo, number)),
+	}
+}
+
+func (s *Server) toGitHubRepository(repo Repository) *github.Repository {
+	return &github.Repository{
+		Name:            github.String(repo.Name),
+		FullName:        github.String(repo.fullName()),
+		Owner:           &github.User{Login: github.String(repo.Owner)},
+		HTMLURL:         github.String(s.server.URL + "/" + repo.fullName()),
+		Topics:          repo.Topics,
+		StargazersCount: github.Int(repo.Stars),
+		Archived:        github.Bool(repo.Archived),
+	}
+}
+
+func (s *Server) searchRepositories(rw http.ResponseWriter, req *http.Request) {
+	var topics []string
+	var archived *bool
+	var fullName string
+
+	for _, field := range strings.Fields(req.URL.Query().Get("q")) {
+		key, value, _ := strings.Cut(field, ":")
+
+		switch key {
+		case "topic":
+			topics = append(topics, value)
+		case "archived":
+			v, _ := strconv.ParseBool(value)
+			archived = &v
+		case "repo":
+			fullName = value
+		}
+	}
+
+	s.mu.Lock()
+	var repos []*github.Repository
+	for _, repo := range s.repositories {
+		if archived != nil && repo.Archived != *archived {
+			continue
+		}
+
+		if fullName != "" && !strings.EqualFold(repo.fullName(), fullName) {
+			continue
+		}
+
+		if !containsAll(repo.Topics, topics) {
+			continue
+		}
+
+		repos = append(repos, s.toGitHubRepository(repo))
+	}
+	s.mu.Unlock()
+
+	slices.SortFunc(repos, func(a, b *github.Repository) int {
+		return strings.Compare(a.GetFullName(), b.GetFullName())
+	})
+
+	writeJSON(rw, http.StatusOK, &github.RepositoriesSearchResult{
+		Total:        github.Int(len(repos)),
+		Repositories: repos,
+	})
+}
+
+func (s *Server) searchIssues(rw http.ResponseWriter, req *http.Request) {
+	var repoURL string
+	for _, field := range strings.Fields(req.URL.Query().Get("q")) {
+		if value, ok := strings.CutPrefix(field, "repo:"); ok {
+			repoURL = s.URL() + path.Join("repos", value)
+		}
+	}
+
+	s.mu.Lock()
+	var issues []*github.Issue
+	for _, issue := range s.issues {
+		if issue.GetState() != "open" {
+			continue
+		}
+
+		if repoURL != "" && !strings.EqualFold(issue.GetRepositoryURL(), repoURL) {
+			continue
+		}
+
+		issues = append(issues, issue)
+	}
+	s.mu.Unlock()
+
+	writeJSON(rw, http.StatusOK, &github.IssuesSearchResult{
+		Total:  github.Int(len(issues)),
+		Issues: issues,
+	})
+}
+
+func (s *Server) getRepository(rw http.ResponseWriter, req *http.Request) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, s.toGitHubRepository(repo))
+}
+
+func (s *Server) createIssue(rw http.ResponseWriter, req *http.Request) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return
+	}
+
+	var issueReq github.IssueRequest
+	if err := json.NewDecoder(req.Body).Decode(&issueReq); err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	issue := s.newIssue(repo.Owner, repo.Name, issueReq.GetTitle(), issueReq.GetBody())
+	s.issues = append(s.issues, issue)
+	s.createdIssues = append(s.createdIssues, issue)
+	s.mu.Unlock()
+
+	writeJSON(rw, http.StatusCreated, issue)
+}
+
+func (s *Server) listTags(rw http.ResponseWriter, req *http.Request) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return
+	}
+
+	tags := make([]*github.RepositoryTag, 0, len(repo.Tags))
+	for _, tag := range repo.Tags {
+		tags = append(tags, &github.RepositoryTag{Name: github.String(tag.Name)})
+	}
+
+	writeJSON(rw, http.StatusOK, tags)
+}
+
+func (s *Server) getContents(rw http.ResponseWriter, req *http.Request) {
+	dir, ok := s.tagDir(rw, req)
+	if !ok {
+		return
+	}
+
+	s.writeFile(rw, dir, req.PathValue("path"))
+}
+
+func (s *Server) getReadme(rw http.ResponseWriter, req *http.Request) {
+	dir, ok := s.tagDir(rw, req)
+	if !ok {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(strings.ToLower(entry.Name()), "readme") {
+			s.writeFile(rw, dir, entry.Name())
+			return
+		}
+	}
+
+	writeError(rw, http.StatusNotFound, "Not Found")
+}
+
+func (s *Server) getLatestRelease(rw http.ResponseWriter, req *http.Request) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return
+	}
+
+	if repo.Release == nil {
+		writeError(rw, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, &github.RepositoryRelease{
+		ID:      github.Int64(1),
+		TagName: github.String(repo.Release.TagName),
+		Assets: []*github.ReleaseAsset{
+			{ID: github.Int64(1), Name: github.String(repo.Release.AssetName)},
+		},
+	})
+}
+
+func (s *Server) downloadAsset(rw http.ResponseWriter, req *http.Request) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return
+	}
+
+	if repo.Release == nil || req.PathValue("id") != "1" {
+		writeError(rw, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	data, err := zipDir(repo.Release.Dir, "")
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = rw.Write(data)
+}
+
+func (s *Server) getZipballLink(rw http.ResponseWriter, req *http.Request) {
+	if _, ok := s.tagDir(rw, req); !ok {
+		return
+	}
+
+	location := fmt.Sprintf("%s/archives/%s/%s/%s", s.server.URL, req.PathValue("owner"), req.PathValue("repo"), req.PathValue("ref"))
+
+	rw.Header().Set("Location", location)
+	rw.WriteHeader(http.StatusFound)
+}
+
+func (s *Server) downloadZipball(rw http.ResponseWriter, req *http.Request) {
+	dir, ok := s.tagDir(rw, req)
+	if !ok {
+		return
+	}
+
+	// GitHub archives contain a root directory.
+	root := fmt.Sprintf("%s-%s-%s", req.PathValue("owner"), req.PathValue("repo"), req.PathValue("ref"))
+
+	data, err := zipDir(dir, root)
+	if err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/zip")
+	_, _ = rw.Write(data)
+}
+
+func (s *Server) repository(rw http.ResponseWriter, req *http.Request) (Repository, bool) {
+	s.mu.Lock()
+	repo, ok := s.repositories[req.PathValue("owner")+"/"+req.PathValue("repo")]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(rw, http.StatusNotFound, "Not Found")
+	}
+
+	return repo, ok
+}
+
+// tagDir returns the fixture directory of the requested ref (the newest tag by default).
+func (s *Server) tagDir(rw http.ResponseWriter, req *http.Request) (string, bool) {
+	repo, ok := s.repository(rw, req)
+	if !ok {
+		return "", false
+	}
+
+	ref := req.PathValue("ref")
+	if ref == "" {
+		ref = req.URL.Query().Get("ref")
+	}
+
+	for _, tag := range repo.Tags {
+		if ref == "" || tag.Name == ref {
+			return tag.Dir, true
+		}
+	}
+
+	writeError(rw, http.StatusNotFound, "No commit found for the ref "+ref)
+
+	return "", false
+}
+
+func (s *Server) writeFile(rw http.ResponseWriter, dir, name string) {
+	if !filepath.IsLocal(name) {
+		writeError(rw, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		writeError(rw, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, &github.RepositoryContent{
+		Type:     github.String("file"),
+		Name:     github.String(path.Base(name)),
+		Path:     github.String(name),
+		Size:     github.Int(len(content)),
+		Encoding: github.String("base64"),
+		Content:  github.String(base64.StdEncoding.EncodeToString(content)),
+	})
+}
+
+func containsAll(values, expected []string) bool {
+	for _, v := range expected {
+		if !slices.Contains(values, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func writeJSON(rw http.ResponseWriter, status int, data interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("X-RateLimit-Limit", "5000")
+	rw.Header().Set("X-RateLimit-Remaining", "4999")
+	rw.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(data)
+}
+
+func writeError(rw http.ResponseWriter, status int, msg string) {
+	writeJSON(rw, status, map[string]string{"message": msg})
+}
@@ -0,0 +1,275 @@
+// Package filestore stores the plugin catalog as a directory of JSON documents and an index file.
+//
+//	<dir>/index.json
+//	<dir>/plugins/<id>.json
+package filestore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+const (
+	indexFile  = "index.json"
+	pluginsDir = "plugins"
+)
+
+// IndexEntry an entry of the catalog index.
+type IndexEntry struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	DisplayName   string    `json:"displayName,omitempty"`
+	Author        string    `json:"author,omitempty"`
+	Type          string    `json:"type,omitempty"`
+	Summary       string    `json:"summary,omitempty"`
+	IconURL       string    `json:"iconUrl,omitempty"`
+	LatestVersion string    `json:"latestVersion,omitempty"`
+	Stars         int       `json:"stars,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Hidden        bool      `json:"hidden,omitempty"`
+	Deprecated    bool      `json:"deprecated,omitempty"`
+	// Path of the plugin document, relative to the index file.
+	Path string `json:"path"`
+}
+
+// Store a plugin catalog stored in a directory.
+type Store struct {
+	dir string
+
+	mu      sync.Mutex
+	plugins map[string]plugin.Plugin
+}
+
+// New creates a Store and loads the existing plugin documents of the directory.
+func New(dir string) (*Store, error) {
+	s := &Store{
+		dir:     dir,
+		plugins: make(map[string]plugin.Plugin),
+	}
+
+	err := os.MkdirAll(filepath.Join(dir, pluginsDir), 0o750)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the catalog directory: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, pluginsDir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plugin documents: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plugin document: %w", err)
+		}
+
+		var p plugin.Plugin
+		if err = json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal plugin document %s: %w", file, err)
+		}
+
+		s.plugins[p.ID] = p
+	}
+
+	return s, nil
+}
+
+// Create creates a plugin.
+func (s *Store) Create(_ context.Context, p plugin.Plugin) error {
+	if p.Name == "" {
+		return errors.New("missing plugin name")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p.ID = newID(p.Name)
+	if _, ok := s.plugins[p.ID]; ok {
+		return &plugin.APIError{StatusCode: http.StatusConflict, Message: "plugin already exists: " + p.Name}
+	}
+
+	p.CreatedAt = time.Now().UTC()
+
+	return s.save(p)
+}
+
+// Update updates a plugin.
+func (s *Store) Update(_ context.Context, p plugin.Plugin) error {
+	if p.ID == "" {
+		return errors.New("missing plugin ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.plugins[p.ID]
+	if !ok {
+		return &plugin.APIError{StatusCode: http.StatusNotFound, Message: "plugin not found: " + p.ID}
+	}
+
+	p.CreatedAt = prev.CreatedAt
+
+	return s.save(p)
+}
+
+// GetByName gets a plugin by name.
+func (s *Store) GetByName(_ context.Context, name string) (*plugin.Plugin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.plugins[newID(name)]
+	if !ok {
+		return nil, &plugin.APIError{StatusCode: http.StatusNotFound, Message: "plugin not found: " + name}
+	}
+
+	return &p, nil
+}
+
+// List lists all the plugins, including the hidden ones.
+func (s *Store) List(_ context.Context) ([]plugin.Plugin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sorted(), nil
+}
+
+// Delete deletes a plugin.
+func (s *Store) Delete(_ context.Context, id string) error {
+	if id == "" {
+		return errors.New("missing plugin ID")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.plugins[id]; !ok {
+		return &plugin.APIError{StatusCode: http.StatusNotFound, Message: "plugin not found: " + id}
+	}
+
+	err := os.Remove(filepath.Join(s.dir, pluginsDir, id+".json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove plugin document: %w", err)
+	}
+
+	delete(s.plugins, id)
+
+	return s.writeIndex()
+}
+
+// Deprecate marks a plugin as deprecated.
+func (s *Store) Deprecate(ctx context.Context, p plugin.Plugin) error {
+	p.Deprecated = true
+
+	return s.Update(ctx, p)
+}
+
+// save writes the plugin document and the index.
+// The caller must hold the lock.
+func (s *Store) save(p plugin.Plugin) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plugin: %w", err)
+	}
+
+	err = writeFile(filepath.Join(s.dir, pluginsDir, p.ID+".json"), data)
+	if err != nil {
+		return fmt.Errorf("failed to write plugin document: %w", err)
+	}
+
+	s.plugins[p.ID] = p
+
+	return s.writeIndex()
+}
+
+// writeIndex writes the index file.
+// The caller must hold the lock.
+func (s *Store) writeIndex() error {
+	plugins := s.sorted()
+
+	index := make([]IndexEntry, 0, len(plugins))
+	for _, p := range plugins {
+		index = append(index, IndexEntry{
+			ID:            p.ID,
+			Name:          p.Name,
+			DisplayName:   p.DisplayName,
+			Author:        p.Author,
+			Type:          p.Type,
+			Summary:       p.Summary,
+			IconURL:       p.IconURL,
+			LatestVersion: p.LatestVersion,
+			Stars:         p.Stars,
+			CreatedAt:     p.CreatedAt,
+			Hidden:        p.Hidden,
+			Deprecated:    p.Deprecated,
+			Path:          path.Join(pluginsDir, p.ID+".json"),
+		})
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	err = writeFile(filepath.Join(s.dir, indexFile), data)
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	return nil
+}
+
+// sorted returns the plugins sorted by name.
+// The caller must hold the lock.
+func (s *Store) sorted() []plugin.Plugin {
+	plugins := make([]plugin.Plugin, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		plugins = append(plugins, p)
+	}
+
+	slices.SortFunc(plugins, func(a, b plugin.Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plugins
+}
+
+// newID computes a stable ID from the plugin name, to keep the document paths stable between runs.
+func newID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+
+	return hex.EncodeToString(sum[:12])
+}
+
+// writeFile writes a file atomically.
+func writeFile(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".piceus-*.json")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,87 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	store, err := New(dir)
+	require.NoError(t, err)
+
+	_, err = store.GetByName(ctx, "github.com/traefik/plugintest")
+
+	var apiErr *plugin.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	err = store.Create(ctx, plugin.Plugin{Name: "github.com/traefik/plugintest", LatestVersion: "v0.1.0", Summary: "test"})
+	require.NoError(t, err)
+
+	err = store.Create(ctx, plugin.Plugin{Name: "github.com/traefik/plugintest"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	p, err := store.GetByName(ctx, "github.com/traefik/plugintest")
+	require.NoError(t, err)
+	assert.NotEmpty(t, p.ID)
+	assert.False(t, p.CreatedAt.IsZero())
+
+	p.LatestVersion = "v0.2.0"
+	err = store.Update(ctx, *p)
+	require.NoError(t, err)
+
+	// The documents are reloaded from the directory.
+	reloaded, err := New(dir)
+	require.NoError(t, err)
+
+	updated, err := reloaded.GetByName(ctx, "github.com/traefik/plugintest")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", updated.LatestVersion)
+	assert.True(t, p.CreatedAt.Equal(updated.CreatedAt))
+
+	index := readIndex(t, dir)
+	require.Len(t, index, 1)
+	assert.Equal(t, p.ID, index[0].ID)
+	assert.Equal(t, "v0.2.0", index[0].LatestVersion)
+	assert.Equal(t, "test", index[0].Summary)
+	assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(index[0].Path)))
+
+	err = reloaded.Deprecate(ctx, *updated)
+	require.NoError(t, err)
+
+	plugins, err := reloaded.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.True(t, plugins[0].Deprecated)
+
+	err = reloaded.Delete(ctx, p.ID)
+	require.NoError(t, err)
+
+	assert.Empty(t, readIndex(t, dir))
+	assert.NoFileExists(t, filepath.Join(dir, pluginsDir, p.ID+".json"))
+}
+
+func readIndex(t *testing.T, dir string) []IndexEntry {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	require.NoError(t, err)
+
+	var index []IndexEntry
+	err = json.Unmarshal(data, &index)
+	require.NoError(t, err)
+
+	return index
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrServiceUnavailable is returned when the circuit breaker is open.
+var ErrServiceUnavailable = errors.New("plugin service unavailable")
+
+// circuitBreaker opens after a number of consecutive failures,
+// and lets a single call through once the cooldown is elapsed (half-open):
+// the other calls are rejected until the result of this probe is recorded.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *circuitBreaker) allow() error {
+	if b == nil || b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if time.Now().Before(b.openUntil) {
+		return fmt.Errorf("%w: %d consecutive failures, retry after %s", ErrServiceUnavailable, b.failures, b.openUntil.Format(time.RFC3339))
+	}
+
+	if b.probing {
+		return fmt.Errorf("%w: %d consecutive failures, a probe is in progress", ErrServiceUnavailable, b.failures)
+	}
+
+	// Half-open: a failure of the probe re-opens the circuit.
+	b.probing = true
+
+	return nil
+}
+
+func (b *circuitBreaker) record(success bool) {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+	}
+}
@@ -29,14 +29,71 @@ func (a *APIError) Error() string {
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+
+	token    string
+	username string
+	password string
+
+	retryMax     int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+
+	breaker *circuitBreaker
+}
+
+// Option configures the plugin service client.
+type Option func(c *Client)
+
+// WithBearerToken authenticates the requests with a bearer token.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithBasicAuth authenticates the requests with a username and a password.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithTimeout sets the timeout of each request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetry retries the idempotent calls (GET, PUT, DELETE) on network and server errors.
+func WithRetry(retryMax int, retryWaitMin, retryWaitMax time.Duration) Option {
+	return func(c *Client) {
+		c.retryMax = retryMax
+		c.retryWaitMin = retryWaitMin
+		c.retryWaitMax = retryWaitMax
+	}
+}
+
+// WithCircuitBreaker stops calling the service after threshold consecutive failures, until the cooldown is elapsed.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(threshold, cooldown)
+	}
 }
 
 // New creates a plugin service client.
-func New(baseURL string) *Client {
-	return &Client{
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
 		baseURL:    baseURL,
 		httpClient: &http.Client{Timeout: 10 * time.Second, Transport: otelhttp.NewTransport(http.DefaultTransport)},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // Create creates a plugin.
@@ -56,7 +113,7 @@ func (c *Client) Create(ctx context.Context, p Plugin) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to call API: %w", err)
 	}
@@ -101,7 +158,7 @@ func (c *Client) Update(ctx context.Context, p Plugin) error {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to call API: %w", err)
 	}
@@ -136,7 +193,7 @@ func (c *Client) GetByName(ctx context.Context, name string) (*Plugin, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call API: %w", err)
 	}
@@ -167,3 +224,162 @@ func (c *Client) GetByName(ctx context.Context, name string) (*Plugin, error) {
 
 	return &plgs[0], nil
 }
+
+// List lists all the plugins, including the hidden ones.
+func (c *Client) List(ctx context.Context) ([]Plugin, error) {
+	baseURL, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	query := baseURL.Query()
+	query.Set("filterHidden", "false")
+	baseURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call API: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return nil, &APIError{
+			Message:    string(body),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	var plgs []Plugin
+	err = json.Unmarshal(body, &plgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarchall data: %w", err)
+	}
+
+	return plgs, nil
+}
+
+// Delete deletes a plugin.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.New("missing plugin ID")
+	}
+
+	baseURL, err := url.Parse(c.baseURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse base URL: %w", err)
+	}
+
+	endpoint, err := baseURL.Parse(path.Join(baseURL.Path, id))
+	if err != nil {
+		return fmt.Errorf("failed to parse endpoint URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call API: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return &APIError{
+			Message:    string(body),
+			StatusCode: resp.StatusCode,
+		}
+	}
+
+	return nil
+}
+
+// Deprecate marks a plugin as deprecated.
+func (c *Client) Deprecate(ctx context.Context, p Plugin) error {
+	p.Deprecated = true
+
+	return c.Update(ctx, p)
+}
+
+// do sends a request with authentication, retries (only for idempotent methods) and circuit breaker.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	retryMax := 0
+	if isIdempotent(req.Method) {
+		retryMax = c.retryMax
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+
+			req.Body = body
+		}
+
+		// The result of an allowed call is always recorded, to end a half-open probe.
+		if err := c.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+
+		failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		c.breaker.record(!failed)
+
+		if !failed || attempt >= retryMax || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(c.backoff(attempt)):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// backoff computes an exponential backoff limited by retryWaitMax.
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryWaitMin << attempt
+	if c.retryWaitMax > 0 && (wait > c.retryWaitMax || wait <= 0) {
+		return c.retryWaitMax
+	}
+
+	return wait
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,199 @@
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_auth(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		opts     []Option
+		expected string
+	}{
+		{
+			desc: "no auth",
+		},
+		{
+			desc:     "bearer token",
+			opts:     []Option{WithBearerToken("secret")},
+			expected: "Bearer secret",
+		},
+		{
+			desc:     "basic auth",
+			opts:     []Option{WithBasicAuth("user", "pass")},
+			expected: "Basic dXNlcjpwYXNz",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				assert.Equal(t, test.expected, req.Header.Get("Authorization"))
+				_, _ = rw.Write([]byte(`[{"id":"1","name":"test"}]`))
+			}))
+			t.Cleanup(server.Close)
+
+			c := New(server.URL, test.opts...)
+
+			p, err := c.GetByName(context.Background(), "test")
+			require.NoError(t, err)
+
+			assert.Equal(t, "1", p.ID)
+		})
+	}
+}
+
+func TestClient_retry(t *testing.T) {
+	testCases := []struct {
+		desc          string
+		call          func(c *Client) error
+		expectedCalls int32
+		expectError   bool
+	}{
+		{
+			desc: "GET is retried",
+			call: func(c *Client) error {
+				_, err := c.GetByName(context.Background(), "test")
+				return err
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc: "PUT is retried",
+			call: func(c *Client) error {
+				return c.Update(context.Background(), Plugin{ID: "1", Name: "test"})
+			},
+			expectedCalls: 3,
+		},
+		{
+			desc: "POST is not retried",
+			call: func(c *Client) error {
+				return c.Create(context.Background(), Plugin{Name: "test"})
+			},
+			expectedCalls: 1,
+			expectError:   true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				if calls.Add(1) < 3 {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				_, _ = rw.Write([]byte(`[{"id":"1","name":"test"}]`))
+			}))
+			t.Cleanup(server.Close)
+
+			c := New(server.URL, WithRetry(3, time.Millisecond, 10*time.Millisecond))
+
+			err := test.call(c)
+			if test.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestClient_circuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	c := New(server.URL, WithCircuitBreaker(2, 100*time.Millisecond))
+
+	for range 2 {
+		_, err := c.GetByName(context.Background(), "test")
+
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+	}
+
+	// The circuit is open.
+	_, err := c.GetByName(context.Background(), "test")
+	require.ErrorIs(t, err, ErrServiceUnavailable)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// The circuit is half-open after the cooldown.
+	time.Sleep(150 * time.Millisecond)
+
+	_, err = c.GetByName(context.Background(), "test")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// A failure in half-open state opens the circuit again.
+	_, err = c.GetByName(context.Background(), "test")
+	require.ErrorIs(t, err, ErrServiceUnavailable)
+}
+
+func TestClient_circuitBreaker_halfOpen(t *testing.T) {
+	var healthy atomic.Bool
+	probing := make(chan struct{})
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		if !healthy.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		close(probing)
+		<-release
+
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+
+	c := New(server.URL, WithCircuitBreaker(1, 50*time.Millisecond))
+
+	_, err := c.GetByName(context.Background(), "test")
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+
+	healthy.Store(true)
+	time.Sleep(100 * time.Millisecond)
+
+	probeErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetByName(context.Background(), "test")
+		probeErr <- err
+	}()
+
+	<-probing
+
+	// Only the probe is let through while the circuit is half-open.
+	_, err = c.GetByName(context.Background(), "test")
+	require.ErrorIs(t, err, ErrServiceUnavailable)
+
+	close(release)
+	require.ErrorAs(t, <-probeErr, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	// The success of the probe closes the circuit.
+	assert.NoError(t, c.breaker.allow())
+}
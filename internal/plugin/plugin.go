@@ -17,11 +17,22 @@ type Plugin struct {
 	IconURL       string                 `json:"iconUrl,omitempty"`
 	BannerURL     string                 `json:"bannerUrl,omitempty"`
 	Readme        string                 `json:"readme,omitempty"`
+	ReadmeHTML    string                 `json:"readmeHtml,omitempty"`
+	ReadmeTOC     []Heading              `json:"readmeToc,omitempty"`
 	LatestVersion string                 `json:"latestVersion,omitempty"`
 	Versions      []string               `json:"versions,omitempty"`
 	Stars         int                    `json:"stars,omitempty"`
 	Snippet       map[string]interface{} `json:"snippet,omitempty"`
+	ConfigSchema  map[string]interface{} `json:"configSchema,omitempty"`
 	CreatedAt     time.Time              `json:"createdAt"`
 	Hidden        bool                   `json:"hidden,omitempty"`
 	UseUnsafe     bool                   `json:"useUnsafe,omitempty"`
+	Deprecated    bool                   `json:"deprecated,omitempty"`
+}
+
+// Heading a heading of the README, used to build the table of contents.
+type Heading struct {
+	Level int    `json:"level"`
+	Title string `json:"title"`
+	ID    string `json:"id"`
 }
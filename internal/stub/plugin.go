@@ -1,28 +1,39 @@
 package main
 
 import (
-	"net/http"
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/internal/stub/service"
 )
 
 func main() {
-	mux := http.NewServeMux()
-
-	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
-		switch req.Method {
-		case http.MethodGet:
-			http.Error(rw, `{"error": "plugin not found"}`, http.StatusNotFound)
-			return
-		case http.MethodPost, http.MethodPut:
-			_, _ = rw.Write([]byte(`{}`))
-			return
-		}
-	})
-
-	//nolint:gosec // only for testing purpose.
-	err := http.ListenAndServe(":8666", mux)
+	addr := flag.String("addr", ":8666", "Address to listen on")
+	dataFile := flag.String("data", "", "JSON file used to persist the plugins (in-memory only if empty)")
+	latency := flag.Duration("latency", 0, "Latency added to each request")
+	errorRate := flag.Float64("error-rate", 0, "Ratio (between 0 and 1) of requests failing with a 503")
+	flag.Parse()
+
+	err := run(*addr, *dataFile, service.Faults{Latency: *latency, ErrorRate: *errorRate})
 	if err != nil {
 		log.Fatal().Err(err).Msg("error")
 	}
 }
+
+func run(addr, dataFile string, faults service.Faults) error {
+	svc, err := service.New(service.WithFile(dataFile), service.WithFaults(faults))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().Str("addr", addr).Msg("Plugin service stub started")
+
+	return svc.ListenAndServe(ctx, addr)
+}
@@ -0,0 +1,358 @@
+// Package service provides an in-memory implementation of the plugin service API.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+// Faults configures the fault injection.
+type Faults struct {
+	// Latency is added to each request.
+	Latency time.Duration
+	// ErrorRate is the ratio (between 0 and 1) of requests failing with a 503.
+	ErrorRate float64
+}
+
+// Option configures the service.
+type Option func(s *Service)
+
+// WithFile persists the plugins into a JSON file.
+func WithFile(filename string) Option {
+	return func(s *Service) {
+		s.filename = filename
+	}
+}
+
+// WithFaults enables the fault injection.
+func WithFaults(faults Faults) Option {
+	return func(s *Service) {
+		s.faults = faults
+	}
+}
+
+// Service an in-memory plugin service.
+type Service struct {
+	mu      sync.RWMutex
+	plugins map[string]plugin.Plugin
+
+	filename string
+	faults   Faults
+
+	mux *http.ServeMux
+}
+
+// New creates a new in-memory plugin service.
+func New(opts ...Option) (*Service, error) {
+	s := &Service{
+		plugins: make(map[string]plugin.Plugin),
+		mux:     http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	s.mux.HandleFunc("GET /{$}", s.list)
+	s.mux.HandleFunc("POST /{$}", s.create)
+	s.mux.HandleFunc("GET /{id}", s.get)
+	s.mux.HandleFunc("PUT /{id}", s.update)
+	s.mux.HandleFunc("DELETE /{id}", s.delete)
+
+	return s, nil
+}
+
+// ServeHTTP serves the plugin service API.
+func (s *Service) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if s.faults.Latency > 0 {
+		select {
+		case <-time.After(s.faults.Latency):
+		case <-req.Context().Done():
+			return
+		}
+	}
+
+	//nolint:gosec // only for testing purpose.
+	if s.faults.ErrorRate > 0 && mrand.Float64() < s.faults.ErrorRate {
+		writeError(rw, http.StatusServiceUnavailable, "injected fault")
+		return
+	}
+
+	s.mux.ServeHTTP(rw, req)
+}
+
+// Plugins returns a snapshot of the stored plugins sorted by name.
+func (s *Service) Plugins() []plugin.Plugin {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	plugins := make([]plugin.Plugin, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		plugins = append(plugins, p)
+	}
+
+	slices.SortFunc(plugins, func(a, b plugin.Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plugins
+}
+
+func (s *Service) list(rw http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get("name")
+	filterHidden := req.URL.Query().Get("filterHidden") != "false"
+
+	var result []plugin.Plugin
+	for _, p := range s.Plugins() {
+		if name != "" && p.Name != name {
+			continue
+		}
+
+		if filterHidden && p.Hidden {
+			continue
+		}
+
+		result = append(result, p)
+	}
+
+	if name != "" && len(result) == 0 {
+		writeError(rw, http.StatusNotFound, "plugin not found")
+		return
+	}
+
+	if result == nil {
+		result = []plugin.Plugin{}
+	}
+
+	writeJSON(rw, http.StatusOK, result)
+}
+
+func (s *Service) get(rw http.ResponseWriter, req *http.Request) {
+	s.mu.RLock()
+	p, ok := s.plugins[req.PathValue("id")]
+	s.mu.RUnlock()
+
+	if !ok {
+		writeError(rw, http.StatusNotFound, "plugin not found")
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, p)
+}
+
+func (s *Service) create(rw http.ResponseWriter, req *http.Request) {
+	var p plugin.Plugin
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if p.Name == "" {
+		writeError(rw, http.StatusBadRequest, "missing plugin name")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.plugins {
+		if existing.Name == p.Name {
+			writeError(rw, http.StatusConflict, "plugin already exists")
+			return
+		}
+	}
+
+	p.ID = newID()
+	p.CreatedAt = time.Now().UTC()
+	s.plugins[p.ID] = p
+
+	if err := s.save(); err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Info().Str("module_name", p.Name).Str("id", p.ID).Msg("Plugin created")
+
+	writeJSON(rw, http.StatusCreated, p)
+}
+
+func (s *Service) update(rw http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+
+	var p plugin.Plugin
+	if err := json.NewDecoder(req.Body).Decode(&p); err != nil {
+		writeError(rw, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, ok := s.plugins[id]
+	if !ok {
+		writeError(rw, http.StatusNotFound, "plugin not found")
+		return
+	}
+
+	p.ID = id
+	p.CreatedAt = prev.CreatedAt
+	s.plugins[id] = p
+
+	if err := s.save(); err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Info().Str("module_name", p.Name).Str("id", p.ID).Msg("Plugin updated")
+
+	writeJSON(rw, http.StatusOK, p)
+}
+
+func (s *Service) delete(rw http.ResponseWriter, req *http.Request) {
+	id := req.PathValue("id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.plugins[id]
+	if !ok {
+		writeError(rw, http.StatusNotFound, "plugin not found")
+		return
+	}
+
+	delete(s.plugins, id)
+
+	if err := s.save(); err != nil {
+		writeError(rw, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	log.Info().Str("module_name", p.Name).Str("id", p.ID).Msg("Plugin deleted")
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Service) load() error {
+	if s.filename == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var plugins []plugin.Plugin
+	if err = json.Unmarshal(data, &plugins); err != nil {
+		return fmt.Errorf("failed to unmarshal data file: %w", err)
+	}
+
+	for _, p := range plugins {
+		s.plugins[p.ID] = p
+	}
+
+	return nil
+}
+
+// save writes the plugins into the data file.
+// The caller must hold the lock.
+func (s *Service) save() error {
+	if s.filename == "" {
+		return nil
+	}
+
+	plugins := make([]plugin.Plugin, 0, len(s.plugins))
+	for _, p := range s.plugins {
+		plugins = append(plugins, p)
+	}
+
+	slices.SortFunc(plugins, func(a, b plugin.Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	data, err := json.MarshalIndent(plugins, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.filename), ".plugins-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create data file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write data file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), s.filename)
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	_, _ = io.ReadFull(rand.Reader, b)
+
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(rw http.ResponseWriter, status int, data interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	if err := json.NewEncoder(rw).Encode(data); err != nil {
+		log.Error().Err(err).Msg("Failed to write response")
+	}
+}
+
+func writeError(rw http.ResponseWriter, status int, msg string) {
+	writeJSON(rw, status, map[string]string{"error": msg})
+}
+
+// ListenAndServe serves the service until the context is canceled.
+func (s *Service) ListenAndServe(ctx context.Context, addr string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = server.Shutdown(context.Background())
+	}()
+
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
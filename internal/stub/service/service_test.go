@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+
+	svc, err := New()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(svc)
+	t.Cleanup(server.Close)
+
+	client := plugin.New(server.URL + "/")
+
+	_, err = client.GetByName(ctx, "github.com/traefik/plugintest")
+
+	var apiErr *plugin.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+
+	err = client.Create(ctx, plugin.Plugin{Name: "github.com/traefik/plugintest", LatestVersion: "v0.1.0", Hidden: true})
+	require.NoError(t, err)
+
+	err = client.Create(ctx, plugin.Plugin{Name: "github.com/traefik/plugintest"})
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.StatusCode)
+
+	p, err := client.GetByName(ctx, "github.com/traefik/plugintest")
+	require.NoError(t, err)
+	assert.NotEmpty(t, p.ID)
+	assert.False(t, p.CreatedAt.IsZero())
+
+	p.LatestVersion = "v0.2.0"
+	err = client.Update(ctx, *p)
+	require.NoError(t, err)
+
+	updated, err := client.GetByName(ctx, "github.com/traefik/plugintest")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.2.0", updated.LatestVersion)
+	assert.Equal(t, p.CreatedAt, updated.CreatedAt)
+
+	plugins, err := client.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, plugins, 1)
+
+	// Hidden plugins are filtered by default.
+	resp, err := http.Get(server.URL + "/")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	var visible []plugin.Plugin
+	err = json.NewDecoder(resp.Body).Decode(&visible)
+	require.NoError(t, err)
+	assert.Empty(t, visible)
+
+	err = client.Delete(ctx, p.ID)
+	require.NoError(t, err)
+
+	assert.Empty(t, svc.Plugins())
+}
+
+func TestService_persistence(t *testing.T) {
+	ctx := context.Background()
+	filename := filepath.Join(t.TempDir(), "plugins.json")
+
+	svc, err := New(WithFile(filename))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(svc)
+	t.Cleanup(server.Close)
+
+	err = plugin.New(server.URL+"/").Create(ctx, plugin.Plugin{Name: "github.com/traefik/plugintest"})
+	require.NoError(t, err)
+
+	reloaded, err := New(WithFile(filename))
+	require.NoError(t, err)
+
+	plugins := reloaded.Plugins()
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "github.com/traefik/plugintest", plugins[0].Name)
+}
+
+func TestService_faults(t *testing.T) {
+	svc, err := New(WithFaults(Faults{ErrorRate: 1}))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(svc)
+	t.Cleanup(server.Close)
+
+	_, err = plugin.New(server.URL + "/").List(context.Background())
+
+	var apiErr *plugin.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+}
@@ -14,6 +14,7 @@ func main() {
 		Usage: "Run piceus",
 		Commands: []*cli.Command{
 			run.Command(),
+			run.ServeCommand(),
 		},
 	}
 
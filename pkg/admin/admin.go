@@ -0,0 +1,137 @@
+// Package admin provides the administration API of Piceus.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+// ErrBusy the analysis is rejected: a run or another analysis is in progress.
+var ErrBusy = errors.New("a run or an analysis is in progress")
+
+// Scrapper the scrapper operations used by the administration API.
+// RunRepository must not wait for a run in progress (it can take hours), but return ErrBusy.
+type Scrapper interface {
+	RunRepository(ctx context.Context, owner, name string) (core.Result, error)
+	Result(owner, name string) (core.Result, bool)
+	Block(fullName string)
+}
+
+// BlocklistRequest the body of a blocklist request.
+type BlocklistRequest struct {
+	// Repository the full name of the repository (owner/name).
+	Repository string `json:"repository"`
+}
+
+// Handler the administration API handler.
+// All the requests must be authenticated with a bearer token.
+type Handler struct {
+	token    []byte
+	scrapper Scrapper
+	mux      *http.ServeMux
+}
+
+// NewHandler creates a new Handler.
+//
+//	POST /analyze/{owner}/{repo}: analyzes a repository, and returns the result (409 if a run or an analysis is in progress).
+//	GET /repos/{owner}/{repo}/status: returns the result of the last analysis of a repository.
+//	POST /blocklist: adds a repository to the blocklist (in-memory, lost on restart).
+func NewHandler(token string, scrapper Scrapper) *Handler {
+	h := &Handler{
+		token:    []byte(token),
+		scrapper: scrapper,
+		mux:      http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("POST /analyze/{owner}/{repo}", h.analyze)
+	h.mux.HandleFunc("GET /repos/{owner}/{repo}/status", h.status)
+	h.mux.HandleFunc("POST /blocklist", h.blocklist)
+
+	return h
+}
+
+// ServeHTTP handles an administration request.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !h.authenticated(req) {
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="piceus"`)
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.mux.ServeHTTP(rw, req)
+}
+
+func (h *Handler) authenticated(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(h.token) == 0 {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), h.token) == 1
+}
+
+func (h *Handler) analyze(rw http.ResponseWriter, req *http.Request) {
+	owner, name := req.PathValue("owner"), req.PathValue("repo")
+
+	logger := log.Ctx(req.Context()).With().Str("repo_name", owner+"/"+name).Logger()
+	logger.Info().Msg("Analysis requested")
+
+	result, err := h.scrapper.RunRepository(logger.WithContext(req.Context()), owner, name)
+	if errors.Is(err, ErrBusy) {
+		logger.Info().Msg("Analysis rejected, busy")
+		http.Error(rw, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to analyze repository")
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	writeJSON(req, rw, result)
+}
+
+func (h *Handler) status(rw http.ResponseWriter, req *http.Request) {
+	result, ok := h.scrapper.Result(req.PathValue("owner"), req.PathValue("repo"))
+	if !ok {
+		http.Error(rw, "repository not analyzed yet", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(req, rw, result)
+}
+
+func (h *Handler) blocklist(rw http.ResponseWriter, req *http.Request) {
+	var body BlocklistRequest
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(rw, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	owner, name, ok := strings.Cut(body.Repository, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		http.Error(rw, "invalid repository, expected owner/name", http.StatusBadRequest)
+		return
+	}
+
+	h.scrapper.Block(body.Repository)
+
+	log.Ctx(req.Context()).Info().Str("repo_name", body.Repository).Msg("Repository blocklisted")
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func writeJSON(req *http.Request, rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(rw).Encode(v); err != nil {
+		log.Ctx(req.Context()).Error().Err(err).Msg("Failed to write response")
+	}
+}
@@ -0,0 +1,173 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+const token = "t0k3n"
+
+type fakeScrapper struct {
+	mu        sync.Mutex
+	results   map[string]core.Result
+	blocklist []string
+}
+
+func (f *fakeScrapper) RunRepository(_ context.Context, owner, name string) (core.Result, error) {
+	if name == "busy" {
+		return core.Result{}, ErrBusy
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := core.Result{Repository: owner + "/" + name, Outcome: core.OutcomeCreated, Plugin: "github.com/" + owner + "/" + name}
+	f.results[owner+"/"+name] = result
+
+	return result, nil
+}
+
+func (f *fakeScrapper) Result(owner, name string) (core.Result, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result, ok := f.results[owner+"/"+name]
+
+	return result, ok
+}
+
+func (f *fakeScrapper) Block(fullName string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.blocklist = append(f.blocklist, fullName)
+}
+
+func TestHandler(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		method     string
+		target     string
+		body       string
+		auth       string
+		wantStatus int
+		wantResult *core.Result
+	}{
+		{
+			desc:       "analyze",
+			method:     http.MethodPost,
+			target:     "/analyze/traefik/plugintest",
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusOK,
+			wantResult: &core.Result{Repository: "traefik/plugintest", Outcome: core.OutcomeCreated, Plugin: "github.com/traefik/plugintest"},
+		},
+		{
+			desc:       "analyze while busy",
+			method:     http.MethodPost,
+			target:     "/analyze/traefik/busy",
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			desc:       "status",
+			method:     http.MethodGet,
+			target:     "/repos/traefik/known/status",
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusOK,
+			wantResult: &core.Result{Repository: "traefik/known", Outcome: core.OutcomeSkipped, SkipReason: core.SkipReasonOpenIssue},
+		},
+		{
+			desc:       "status of an unknown repository",
+			method:     http.MethodGet,
+			target:     "/repos/traefik/unknown/status",
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			desc:       "blocklist",
+			method:     http.MethodPost,
+			target:     "/blocklist",
+			body:       `{"repository":"traefik/plugintest"}`,
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc:       "blocklist invalid repository",
+			method:     http.MethodPost,
+			target:     "/blocklist",
+			body:       `{"repository":"plugintest"}`,
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc:       "missing token",
+			method:     http.MethodPost,
+			target:     "/analyze/traefik/plugintest",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:       "invalid token",
+			method:     http.MethodPost,
+			target:     "/analyze/traefik/plugintest",
+			auth:       "Bearer invalid",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			desc:       "wrong method",
+			method:     http.MethodGet,
+			target:     "/analyze/traefik/plugintest",
+			auth:       "Bearer " + token,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			scrapper := &fakeScrapper{results: map[string]core.Result{
+				"traefik/known": {Repository: "traefik/known", Outcome: core.OutcomeSkipped, SkipReason: core.SkipReasonOpenIssue},
+			}}
+
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			if test.auth != "" {
+				req.Header.Set("Authorization", test.auth)
+			}
+
+			rw := httptest.NewRecorder()
+			NewHandler(token, scrapper).ServeHTTP(rw, req)
+
+			assert.Equal(t, test.wantStatus, rw.Code)
+
+			if test.wantResult != nil {
+				var result core.Result
+				err := json.NewDecoder(rw.Body).Decode(&result)
+				require.NoError(t, err)
+
+				assert.Equal(t, *test.wantResult, result)
+			}
+		})
+	}
+}
+
+func TestHandler_blocklist(t *testing.T) {
+	scrapper := &fakeScrapper{results: map[string]core.Result{}}
+
+	req := httptest.NewRequest(http.MethodPost, "/blocklist", strings.NewReader(`{"repository":"traefik/plugintest"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	rw := httptest.NewRecorder()
+	NewHandler(token, scrapper).ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusNoContent, rw.Code)
+	assert.Equal(t, []string{"traefik/plugintest"}, scrapper.blocklist)
+}
@@ -2,17 +2,34 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/go-github/v57/github"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
+	headerRateLimit     = "X-RateLimit-Limit"
 	headerRateRemaining = "X-RateLimit-Remaining"
 	headerRateReset     = "X-RateLimit-Reset"
+	headerRateResource  = "X-RateLimit-Resource"
+)
+
+// GitHub rate limit resources.
+// https://docs.github.com/en/rest/rate-limit/rate-limit#about-rate-limits
+const (
+	resourceCore       = "core"
+	resourceSearch     = "search"
+	resourceCodeSearch = "code_search"
+	resourceGraphQL    = "graphql"
 )
 
 // adaptiveRateLimiter manages GitHub API rate limiting using responses headers.
@@ -23,89 +40,255 @@ type adaptiveRateLimiter struct {
 }
 
 func (arl adaptiveRateLimiter) Apply(_ context.Context, c *Client) error {
-	c.client.Transport = &adaptiveRateLimiterTripper{
-		remaining:    arl.remaining,
-		resetTime:    arl.resetTime,
+	gauge, err := otel.Meter("piceus").Int64ObservableGauge(
+		"github.ratelimit.remaining",
+		metric.WithDescription("Number of requests remaining in the current rate limit window, by GitHub resource."),
+		metric.WithUnit("requests"),
+	)
+	if err != nil {
+		return fmt.Errorf("creating gauge: %w", err)
+	}
+
+	rl := &adaptiveRateLimiterTripper{
+		buckets: make(map[string]*rateBucket),
+		seed: rateBucket{
+			remaining: arl.remaining,
+			resetTime: arl.resetTime,
+		},
 		safetyBuffer: arl.safetyBuffer,
 
 		next: c.client.Transport,
 	}
+
+	_, err = otel.Meter("piceus").RegisterCallback(rl.observe, gauge)
+	if err != nil {
+		return fmt.Errorf("registering gauge callback: %w", err)
+	}
+
+	rl.remainingGauge = gauge
+
+	c.client.Transport = rl
+	c.rateLimiter = rl
+
 	return nil
 }
 
+// rateBucket holds the rate limit state of a GitHub resource.
+type rateBucket struct {
+	limit     int
+	remaining int
+	resetTime time.Time // zero when the state of the bucket is unknown
+}
+
 type adaptiveRateLimiterTripper struct {
-	mu           sync.Mutex
-	remaining    int
-	resetTime    time.Time
+	mu      sync.Mutex
+	buckets map[string]*rateBucket
+	// seed is the initial state of a bucket until the first response of its resource is received.
+	seed         rateBucket
 	safetyBuffer int // Number of requests to keep as buffer
 
+	remainingGauge metric.Int64ObservableGauge
+
 	next http.RoundTripper
 }
 
 func (rl *adaptiveRateLimiterTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	resp, err := rl.next.RoundTrip(req)
+	resource := resourceFromPath(req.URL.Path)
 
+	if err := rl.wait(req.Context(), resource); err != nil {
+		return nil, err
+	}
+
+	resp, err := rl.next.RoundTrip(req)
 	if resp == nil {
 		return nil, err
 	}
 
+	if name := resp.Header.Get(headerRateResource); name != "" {
+		resource = name
+	}
+
+	rl.update(resource, resp.Header)
+
+	return resp, err
+}
+
+// wait blocks until the given resource has enough requests remaining.
+func (rl *adaptiveRateLimiterTripper) wait(ctx context.Context, resource string) error {
+	for {
+		rl.mu.Lock()
+		shouldWait, waitTime := rl.shouldWait(resource)
+		rl.mu.Unlock()
+
+		if !shouldWait {
+			return nil
+		}
+
+		log.Debug().
+			Str("resource", resource).
+			Dur("waitTime", waitTime).
+			Msg("Adaptive throttling: waiting for rate limit reset")
+
+		select {
+		case <-time.After(waitTime):
+			// Check again after wait
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (rl *adaptiveRateLimiterTripper) update(resource string, header http.Header) {
+	remaining := header.Get(headerRateRemaining)
+	reset := header.Get(headerRateReset)
+
+	if remaining == "" && reset == "" {
+		return
+	}
+
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	if remaining := resp.Header.Get(headerRateRemaining); remaining != "" {
-		rl.remaining, _ = strconv.Atoi(remaining)
+	bucket := rl.bucket(resource)
+
+	if limit := header.Get(headerRateLimit); limit != "" {
+		bucket.limit, _ = strconv.Atoi(limit)
+	}
+
+	if remaining != "" {
+		bucket.remaining, _ = strconv.Atoi(remaining)
 	}
 
-	if reset := resp.Header.Get(headerRateReset); reset != "" {
+	if reset != "" {
 		if v, _ := strconv.ParseInt(reset, 10, 64); v != 0 {
-			rl.resetTime = time.Unix(v, 0)
+			bucket.resetTime = time.Unix(v, 0)
 		}
 	}
 
 	log.Debug().
-		Int("remaining", rl.remaining).
-		Time("reset", rl.resetTime).
+		Str("resource", resource).
+		Int("limit", bucket.limit).
+		Int("remaining", bucket.remaining).
+		Time("reset", bucket.resetTime).
 		Msg("Updated rate limit info from GitHub responses")
+}
 
-	if shouldWait, waitTime := rl.shouldWait(); shouldWait {
-		log.Debug().
-			Dur("waitTime", waitTime).
-			Msg("Adaptive throttling: waiting for rate limit reset")
+// bucket returns the bucket of a resource, creating it from the seed if needed.
+// The caller must hold the lock.
+func (rl *adaptiveRateLimiterTripper) bucket(resource string) *rateBucket {
+	bucket, ok := rl.buckets[resource]
+	if !ok {
+		bucket = &rateBucket{
+			limit:     rl.seed.remaining,
+			remaining: rl.seed.remaining,
+			resetTime: rl.seed.resetTime,
+		}
+		rl.buckets[resource] = bucket
+	}
 
-		select {
-		case <-time.After(waitTime):
-			// Continue after wait
-		case <-req.Context().Done():
-			return nil, req.Context().Err()
+	return bucket
+}
+
+// observe reports the remaining requests of the resources with a known state.
+func (rl *adaptiveRateLimiterTripper) observe(_ context.Context, o metric.Observer) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for resource, bucket := range rl.buckets {
+		if bucket.resetTime.IsZero() {
+			continue
 		}
+
+		o.ObserveInt64(rl.remainingGauge, int64(bucket.remaining), metric.WithAttributes(attribute.String("resource", resource)))
 	}
 
-	return resp, err
+	return nil
 }
 
-// ShouldWait determines if we should wait before making the next request.
-func (rl *adaptiveRateLimiterTripper) shouldWait() (bool, time.Duration) {
+// shouldWait determines if we should wait before making the next request on the given resource.
+// The caller must hold the lock.
+func (rl *adaptiveRateLimiterTripper) shouldWait(resource string) (bool, time.Duration) {
+	bucket := rl.bucket(resource)
+
+	// The state is unknown until the next response.
+	if bucket.resetTime.IsZero() {
+		return false, 0
+	}
+
 	// If we have enough requests remaining, no need to wait
-	if rl.remaining > rl.safetyBuffer {
+	if bucket.remaining > rl.safetyBuffer {
 		return false, 0
 	}
 
 	// Calculate time until reset
 	now := time.Now()
-	if now.After(rl.resetTime) {
-		// Rate limit should have reset, update our state
-		rl.remaining = 30 // Reset to default GitHub limit
-		rl.resetTime = now.Add(time.Minute)
+	if now.After(bucket.resetTime) {
+		// Rate limit should have reset, the next response will provide the new state.
+		bucket.remaining = bucket.limit
+		bucket.resetTime = time.Time{}
 		return false, 0
 	}
 
 	// We're close to the limit, wait until reset
-	waitTime := rl.resetTime.Sub(now)
+	waitTime := bucket.resetTime.Sub(now)
 
 	log.Debug().
-		Int("remaining", rl.remaining).
+		Str("resource", resource).
+		Int("remaining", bucket.remaining).
 		Dur("waitTime", waitTime).
 		Msg("Rate limit approaching, waiting for reset")
 
 	return true, waitTime
 }
+
+type rateLimitResponse struct {
+	Resources map[string]struct {
+		Limit     int   `json:"limit"`
+		Remaining int   `json:"remaining"`
+		Reset     int64 `json:"reset"`
+	} `json:"resources"`
+}
+
+// sync initializes the buckets of all the resources from the GitHub rate limit API.
+func (rl *adaptiveRateLimiterTripper) sync(ctx context.Context, gh *github.Client) error {
+	req, err := gh.NewRequest(http.MethodGet, "rate_limit", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var limits rateLimitResponse
+	_, err = gh.Do(ctx, req, &limits)
+	if err != nil {
+		return fmt.Errorf("failed to get rate limits: %w", err)
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	for resource, rate := range limits.Resources {
+		rl.buckets[resource] = &rateBucket{
+			limit:     rate.Limit,
+			remaining: rate.Remaining,
+			resetTime: time.Unix(rate.Reset, 0),
+		}
+	}
+
+	return nil
+}
+
+// resourceFromPath guesses the rate limit resource used by a request before sending it.
+func resourceFromPath(p string) string {
+	// GitHub Enterprise Server API prefix.
+	p = strings.TrimPrefix(p, "/api/v3")
+
+	switch {
+	case strings.HasPrefix(p, "/search/code"):
+		return resourceCodeSearch
+	case strings.HasPrefix(p, "/search/"):
+		return resourceSearch
+	case strings.HasPrefix(p, "/graphql"), strings.HasPrefix(p, "/api/graphql"):
+		return resourceGraphQL
+	default:
+		return resourceCore
+	}
+}
@@ -1,15 +1,25 @@
 package client
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/google/go-github/v57/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestAdaptiveRateLimiter_ShouldWait(t *testing.T) {
 	tests := []struct {
 		desc           string
+		limit          int
 		remaining      int
 		resetTime      time.Time
 		safetyBuffer   int
@@ -41,6 +51,7 @@ func TestAdaptiveRateLimiter_ShouldWait(t *testing.T) {
 		},
 		{
 			desc:         "resets state when reset time passed",
+			limit:        30,
 			remaining:    5,
 			resetTime:    time.Now().Add(-30 * time.Second),
 			safetyBuffer: 15,
@@ -48,23 +59,32 @@ func TestAdaptiveRateLimiter_ShouldWait(t *testing.T) {
 		},
 		{
 			desc:         "handles zero requests with expired reset time",
+			limit:        5000,
 			remaining:    0,
 			resetTime:    time.Now().Add(-1 * time.Minute),
 			safetyBuffer: 15,
 			expectWait:   false,
 		},
+		{
+			desc:         "continues when state is unknown",
+			remaining:    0,
+			safetyBuffer: 15,
+			expectWait:   false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.desc, func(t *testing.T) {
 			t.Parallel()
 
-			rl := adaptiveRateLimiterTripper{}
-			rl.remaining = tt.remaining
-			rl.resetTime = tt.resetTime
-			rl.safetyBuffer = tt.safetyBuffer
+			rl := adaptiveRateLimiterTripper{
+				buckets: map[string]*rateBucket{
+					resourceSearch: {limit: tt.limit, remaining: tt.remaining, resetTime: tt.resetTime},
+				},
+				safetyBuffer: tt.safetyBuffer,
+			}
 
-			shouldWait, waitTime := rl.shouldWait()
+			shouldWait, waitTime := rl.shouldWait(resourceSearch)
 
 			assert.Equal(t, tt.expectWait, shouldWait)
 
@@ -75,10 +95,142 @@ func TestAdaptiveRateLimiter_ShouldWait(t *testing.T) {
 			}
 
 			// If reset time was in the past, check that state was reset
-			if tt.resetTime.Before(time.Now()) && !tt.expectWait {
-				assert.Equal(t, 30, rl.remaining)
-				assert.True(t, rl.resetTime.After(time.Now()))
+			if !tt.resetTime.IsZero() && tt.resetTime.Before(time.Now()) && !tt.expectWait {
+				assert.Equal(t, tt.limit, rl.buckets[resourceSearch].remaining)
+				assert.True(t, rl.buckets[resourceSearch].resetTime.IsZero())
 			}
 		})
 	}
 }
+
+func TestAdaptiveRateLimiter_perResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/search/repositories" {
+			w.Header().Set(headerRateResource, resourceSearch)
+			w.Header().Set(headerRateLimit, "30")
+			w.Header().Set(headerRateRemaining, "0")
+			w.Header().Set(headerRateReset, strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	rl := &adaptiveRateLimiterTripper{
+		buckets:      make(map[string]*rateBucket),
+		safetyBuffer: 1,
+		next:         http.DefaultTransport,
+	}
+	client := &http.Client{Transport: rl}
+
+	resp, err := client.Get(server.URL + "/search/repositories")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	// The search budget is exhausted, but core calls must not be blocked.
+	start := time.Now()
+	resp, err = client.Get(server.URL + "/repos/traefik/piceus/tags")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+
+	// The search budget is exhausted, search calls must wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/search/issues", nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAdaptiveRateLimiter_sync(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/rate_limit", r.URL.Path)
+
+		_, _ = w.Write([]byte(`{"resources":{` +
+			`"core":{"limit":5000,"remaining":4999,"reset":` + strconv.FormatInt(reset.Unix(), 10) + `},` +
+			`"search":{"limit":30,"remaining":2,"reset":` + strconv.FormatInt(reset.Unix(), 10) + `}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	rl := &adaptiveRateLimiterTripper{
+		buckets: make(map[string]*rateBucket),
+		next:    http.DefaultTransport,
+	}
+
+	gh := github.NewClient(&http.Client{Transport: rl})
+	gh.BaseURL, _ = url.Parse(server.URL + "/")
+
+	err := rl.sync(context.Background(), gh)
+	require.NoError(t, err)
+
+	assert.Equal(t, &rateBucket{limit: 5000, remaining: 4999, resetTime: reset}, rl.buckets[resourceCore])
+	assert.Equal(t, &rateBucket{limit: 30, remaining: 2, resetTime: reset}, rl.buckets[resourceSearch])
+}
+
+func Test_resourceFromPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/search/repositories", expected: resourceSearch},
+		{path: "/search/issues", expected: resourceSearch},
+		{path: "/search/code", expected: resourceCodeSearch},
+		{path: "/graphql", expected: resourceGraphQL},
+		{path: "/repos/traefik/piceus/tags", expected: resourceCore},
+		{path: "/api/v3/search/repositories", expected: resourceSearch},
+		{path: "/api/v3/repos/traefik/piceus/readme", expected: resourceCore},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, resourceFromPath(test.path))
+		})
+	}
+}
+
+func TestAdaptiveRateLimiter_observe(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	gauge, err := meter.Int64ObservableGauge("github.ratelimit.remaining")
+	require.NoError(t, err)
+
+	rl := &adaptiveRateLimiterTripper{
+		buckets: map[string]*rateBucket{
+			resourceCore:   {limit: 5000, remaining: 4200, resetTime: time.Now().Add(time.Hour)},
+			resourceSearch: {limit: 30, remaining: 12, resetTime: time.Now().Add(time.Minute)},
+			// Unknown state.
+			resourceGraphQL: {limit: 5000, remaining: 5000},
+		},
+		remainingGauge: gauge,
+	}
+
+	_, err = meter.RegisterCallback(rl.observe, gauge)
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	err = reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	data, ok := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Gauge[int64])
+	require.True(t, ok)
+
+	remaining := make(map[string]int64)
+	for _, dp := range data.DataPoints {
+		resource, _ := dp.Attributes.Value("resource")
+		remaining[resource.AsString()] = dp.Value
+	}
+
+	assert.Equal(t, map[string]int64{resourceCore: 4200, resourceSearch: 12}, remaining)
+}
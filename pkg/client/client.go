@@ -2,7 +2,10 @@ package client
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/go-github/v57/github"
@@ -18,6 +21,13 @@ type Option interface {
 type Client struct {
 	gh     *github.Client
 	client *http.Client
+
+	rateLimiter *adaptiveRateLimiterTripper
+	retries     *atomic.Int64
+
+	// GitHub Enterprise Server URLs.
+	baseURL   string
+	uploadURL string
 }
 
 // New creates a new client with optional middleware.
@@ -43,6 +53,14 @@ func New(ctx context.Context, options ...Option) (*Client, error) {
 
 	c.gh = github.NewClient(c.client)
 
+	if c.baseURL != "" {
+		var err error
+		c.gh, err = c.gh.WithEnterpriseURLs(c.baseURL, c.uploadURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set GitHub Enterprise URLs: %w", err)
+		}
+	}
+
 	return c, nil
 }
 
@@ -51,12 +69,37 @@ func (c *Client) GithubClient() *github.Client {
 	return c.gh
 }
 
+// SyncRateLimits initializes the rate limiter state of all GitHub resources from the rate limit API.
+func (c *Client) SyncRateLimits(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return errors.New("rate limiter not enabled")
+	}
+
+	return c.rateLimiter.sync(ctx, c.gh)
+}
+
+// Retries returns the number of retried requests since the creation of the client.
+func (c *Client) Retries() int64 {
+	if c.retries == nil {
+		return 0
+	}
+
+	return c.retries.Load()
+}
+
 // WithToken adds authentification middleware to HTTP client.
 func WithToken(token string) Option {
 	a := authClient{token: token}
 	return a
 }
 
+// WithEnterpriseURLs configures the client to use a GitHub Enterprise Server instance.
+// The upload URL defaults to the base URL.
+func WithEnterpriseURLs(baseURL, uploadURL string) Option {
+	e := enterpriseClient{baseURL: baseURL, uploadURL: uploadURL}
+	return e
+}
+
 // WithMetrics adds metrics middleware to HTTP client.
 func WithMetrics(enable bool) Option {
 	m := metricsClient{enabled: enable}
@@ -64,9 +107,11 @@ func WithMetrics(enable bool) Option {
 }
 
 // WithRateLimiter adds adaptative rate limiter middleware to HTTP client.
+// The rate limit state is tracked per GitHub resource (core, search, ...),
+// remaining and resetTime are the initial state of a resource until its first response.
 func WithRateLimiter(remaining, safetyBuffer int, resetTime time.Time) Option {
 	arl := &adaptiveRateLimiter{
-		remaining:    remaining, // GitHub search API default limit
+		remaining:    remaining,
 		resetTime:    resetTime,
 		safetyBuffer: safetyBuffer, // requests to keep as safety buffer
 	}
@@ -74,6 +119,7 @@ func WithRateLimiter(remaining, safetyBuffer int, resetTime time.Time) Option {
 }
 
 // WithRetry adds retry middleware to HTTP client.
+// The retry policy handles GitHub primary and secondary rate limits (Retry-After, X-RateLimit-Reset).
 func WithRetry(retryMax int, retryWaitMin time.Duration) Option {
 	r := retryClient{
 		retryClient: retryablehttp.NewClient(),
@@ -30,6 +30,7 @@ func TestNewWithOptions(t *testing.T) {
 		wantRequest              assert.ValueAssertionFunc
 		wantResponseTimeInterval []time.Duration
 		wantMetricRequestsTotal  int64
+		wantRetries              int64
 		wantStatusCode           int
 	}{
 		{
@@ -74,7 +75,7 @@ func TestNewWithOptions(t *testing.T) {
 			wantStatusCode:           http.StatusOK,
 		},
 		{
-			desc:    "with rate limit (limit from response)",
+			desc:    "with rate limit (limit from response applies to the next request)",
 			options: []Option{WithRateLimiter(10, 1, time.Now().Add(10*time.Second))},
 			responses: func() []http.Response {
 				return []http.Response{{
@@ -86,7 +87,7 @@ func TestNewWithOptions(t *testing.T) {
 					Body: io.NopCloser(strings.NewReader("{}")),
 				}}
 			},
-			wantResponseTimeInterval: []time.Duration{time.Second, 2*time.Second + 500*time.Millisecond},
+			wantResponseTimeInterval: []time.Duration{0, 500 * time.Millisecond},
 			wantStatusCode:           http.StatusOK,
 		},
 		{
@@ -117,8 +118,37 @@ func TestNewWithOptions(t *testing.T) {
 				}
 			},
 			wantResponseTimeInterval: []time.Duration{time.Second, time.Second + 100*time.Millisecond},
+			wantRetries:              1,
+			wantStatusCode:           http.StatusOK,
+		},
+		{
+			desc:    "with retry (secondary rate limit)",
+			options: []Option{WithRetry(1, 100*time.Millisecond)},
+			responses: func() []http.Response {
+				return []http.Response{
+					{
+						StatusCode: http.StatusForbidden,
+						Header:     map[string][]string{"Retry-After": {"1"}},
+						Body:       io.NopCloser(strings.NewReader(`{"message":"You have exceeded a secondary rate limit."}`)),
+					},
+					{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))},
+				}
+			},
+			wantResponseTimeInterval: []time.Duration{time.Second, time.Second + 500*time.Millisecond},
+			wantRetries:              1,
 			wantStatusCode:           http.StatusOK,
 		},
+		{
+			desc:    "with retry (forbidden)",
+			options: []Option{WithRetry(1, time.Second)},
+			responses: func() []http.Response {
+				return []http.Response{
+					{StatusCode: http.StatusForbidden, Body: io.NopCloser(strings.NewReader(`{"message":"Forbidden"}`))},
+				}
+			},
+			wantResponseTimeInterval: []time.Duration{0, 100 * time.Millisecond},
+			wantStatusCode:           http.StatusForbidden,
+		},
 		{
 			desc:    "with retry, shouldn't follow redirects",
 			options: []Option{WithRetry(1, time.Second)},
@@ -159,6 +189,7 @@ func TestNewWithOptions(t *testing.T) {
 			},
 			wantResponseTimeInterval: []time.Duration{time.Second, 2*time.Second + 500*time.Millisecond},
 			wantMetricRequestsTotal:  2,
+			wantRetries:              1,
 			wantStatusCode:           http.StatusOK,
 		},
 	}
@@ -216,6 +247,8 @@ func TestNewWithOptions(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, tt.wantStatusCode, resp.StatusCode)
 
+			assert.Equal(t, tt.wantRetries, c.Retries())
+
 			if len(tt.wantResponseTimeInterval) == 2 {
 				assert.WithinRange(t, time.Now(), start.Add(tt.wantResponseTimeInterval[0]), start.Add(tt.wantResponseTimeInterval[1]))
 			}
@@ -246,3 +279,49 @@ func TestNewWithOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestNew_enterpriseURLs(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		baseURL           string
+		uploadURL         string
+		expectedBaseURL   string
+		expectedUploadURL string
+	}{
+		{
+			desc:              "github.com",
+			expectedBaseURL:   "https://api.github.com/",
+			expectedUploadURL: "https://uploads.github.com/",
+		},
+		{
+			desc:              "enterprise",
+			baseURL:           "https://github.example.com",
+			uploadURL:         "https://uploads.github.example.com",
+			expectedBaseURL:   "https://github.example.com/api/v3/",
+			expectedUploadURL: "https://uploads.github.example.com/api/uploads/",
+		},
+		{
+			desc:              "enterprise without upload URL",
+			baseURL:           "https://github.example.com/",
+			expectedBaseURL:   "https://github.example.com/api/v3/",
+			expectedUploadURL: "https://github.example.com/api/uploads/",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var options []Option
+			if test.baseURL != "" {
+				options = append(options, WithEnterpriseURLs(test.baseURL, test.uploadURL))
+			}
+
+			c, err := New(context.Background(), options...)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedBaseURL, c.GithubClient().BaseURL.String())
+			assert.Equal(t, test.expectedUploadURL, c.GithubClient().UploadURL.String())
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package client
+
+import "context"
+
+type enterpriseClient struct {
+	baseURL   string
+	uploadURL string
+}
+
+func (e enterpriseClient) Apply(_ context.Context, c *Client) error {
+	c.baseURL = e.baseURL
+
+	c.uploadURL = e.uploadURL
+	if c.uploadURL == "" {
+		c.uploadURL = e.baseURL
+	}
+
+	return nil
+}
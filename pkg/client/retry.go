@@ -1,13 +1,28 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
+const headerRetryAfter = "Retry-After"
+
+// maxErrorBodySize is the maximum size of a response body read to detect secondary rate limits.
+const maxErrorBodySize = 64 * 1024
+
 type retryClient struct {
 	retryClient *retryablehttp.Client
 }
@@ -21,8 +36,152 @@ func (r retryClient) Apply(ctx context.Context, c *Client) error {
 		CheckRedirect: func(_ *http.Request, _ []*http.Request) error { return http.ErrUseLastResponse },
 	}
 	r.retryClient.Logger = log.Ctx(ctx)
+	r.retryClient.CheckRetry = githubRetryPolicy(r.retryClient.RetryWaitMax)
+	r.retryClient.Backoff = githubBackoff
+
+	retryCounter, err := otel.Meter("piceus").Int64Counter(
+		"http.retries.total",
+		metric.WithDescription("Number of retried API calls."),
+		metric.WithUnit("requests"),
+	)
+	if err != nil {
+		return fmt.Errorf("creating counter: %w", err)
+	}
+
+	c.retries = &atomic.Int64{}
+
+	r.retryClient.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attemptNum int) {
+		if attemptNum == 0 {
+			return
+		}
+
+		c.retries.Add(1)
+		retryCounter.Add(req.Context(), 1, metric.WithAttributes(
+			attribute.String("method", req.Method),
+			attribute.String("host", req.URL.Host),
+		))
+	}
 
 	c.client.Transport = &retryablehttp.RoundTripper{Client: r.retryClient}
 
 	return nil
 }
+
+// githubRetryPolicy extends the default retry policy with the GitHub secondary rate limits.
+// A rate-limited request is not retried if the rate limit resets after maxWait:
+// the response is returned, and the adaptive rate limiter waits for the reset before the next requests.
+// https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api#exceeding-the-rate-limit
+func githubRetryPolicy(maxWait time.Duration) retryablehttp.CheckRetry {
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		// do not retry on context.Canceled or context.DeadlineExceeded
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if err != nil || resp == nil {
+			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+		}
+
+		switch resp.StatusCode {
+		case http.StatusForbidden, http.StatusTooManyRequests:
+			if isRateLimited(resp) {
+				if wait, ok := rateLimitWait(resp); ok && wait > maxWait {
+					log.Ctx(ctx).Debug().
+						Int("status", resp.StatusCode).
+						Dur("wait", wait).
+						Msg("GitHub rate limit exceeded, the reset is too far to retry")
+
+					return false, nil
+				}
+
+				log.Ctx(ctx).Debug().
+					Int("status", resp.StatusCode).
+					Msg("GitHub rate limit exceeded, retrying")
+
+				return true, nil
+			}
+
+			// A 403 without rate limit information is a real permission error.
+			return resp.StatusCode == http.StatusTooManyRequests, nil
+
+		case http.StatusNotFound:
+			return false, nil
+
+		default:
+			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+		}
+	}
+}
+
+// githubBackoff honors the Retry-After and X-RateLimit-Reset headers, within maxWait, before falling back to an exponential backoff.
+func githubBackoff(minWait, maxWait time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) {
+		if wait, ok := rateLimitWait(resp); ok {
+			return min(max(wait, minWait), maxWait)
+		}
+	}
+
+	return retryablehttp.DefaultBackoff(minWait, maxWait, attemptNum, resp)
+}
+
+// rateLimitWait returns the time to wait before retrying a rate-limited request, from the Retry-After or X-RateLimit-Reset headers.
+func rateLimitWait(resp *http.Response) (time.Duration, bool) {
+	if wait, ok := parseRetryAfter(resp.Header.Get(headerRetryAfter)); ok {
+		return wait, true
+	}
+
+	if resp.Header.Get(headerRateRemaining) == "0" {
+		if v, err := strconv.ParseInt(resp.Header.Get(headerRateReset), 10, 64); err == nil {
+			return max(time.Until(time.Unix(v, 0)), 0), true
+		}
+	}
+
+	return 0, false
+}
+
+// isRateLimited checks if a 403/429 response is caused by a primary or secondary rate limit.
+func isRateLimited(resp *http.Response) bool {
+	if resp.Header.Get(headerRetryAfter) != "" || resp.Header.Get(headerRateRemaining) == "0" {
+		return true
+	}
+
+	if resp.Body == nil {
+		return false
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	_ = resp.Body.Close()
+
+	// Restores the body to allow the caller to read the response.
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err != nil {
+		return false
+	}
+
+	msg := strings.ToLower(string(body))
+
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection")
+}
+
+// parseRetryAfter parses the Retry-After header: seconds or HTTP-date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return max(time.Until(date), 0), true
+}
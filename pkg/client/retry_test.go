@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_githubRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		resp     *http.Response
+		expected bool
+	}{
+		{
+			desc:     "success",
+			resp:     &http.Response{StatusCode: http.StatusOK, Header: http.Header{}},
+			expected: false,
+		},
+		{
+			desc:     "not found",
+			resp:     &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}},
+			expected: false,
+		},
+		{
+			desc: "forbidden",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"message":"Resource not accessible by integration"}`)),
+			},
+			expected: false,
+		},
+		{
+			desc: "forbidden with Retry-After",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{headerRetryAfter: {"60"}},
+			},
+			expected: true,
+		},
+		{
+			desc: "forbidden with primary rate limit exhausted",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"X-Ratelimit-Remaining": {"0"}},
+			},
+			expected: true,
+		},
+		{
+			desc: "forbidden with secondary rate limit message",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`)),
+			},
+			expected: true,
+		},
+		{
+			desc: "forbidden with abuse detection message",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"message":"You have triggered an abuse detection mechanism."}`)),
+			},
+			expected: true,
+		},
+		{
+			desc: "rate limit reset after the maximum wait",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": {"0"},
+					"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+				},
+			},
+			expected: false,
+		},
+		{
+			desc:     "Retry-After after the maximum wait",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{headerRetryAfter: {"3600"}}},
+			expected: false,
+		},
+		{
+			desc:     "too many requests",
+			resp:     &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+			expected: true,
+		},
+		{
+			desc:     "server error",
+			resp:     &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}},
+			expected: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			retry, err := githubRetryPolicy(5*time.Minute)(context.Background(), test.resp, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, retry)
+
+			if test.resp.Body != nil {
+				// The body must still be readable by the caller.
+				body, err := io.ReadAll(test.resp.Body)
+				require.NoError(t, err)
+				assert.NotEmpty(t, body)
+			}
+		})
+	}
+}
+
+func Test_githubBackoff(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		resp     *http.Response
+		expected [2]time.Duration
+	}{
+		{
+			desc:     "no response",
+			expected: [2]time.Duration{time.Second, time.Second},
+		},
+		{
+			desc: "Retry-After in seconds",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{headerRetryAfter: {"60"}},
+			},
+			expected: [2]time.Duration{time.Minute, time.Minute},
+		},
+		{
+			desc: "Retry-After as date",
+			resp: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{headerRetryAfter: {time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)}},
+			},
+			expected: [2]time.Duration{58 * time.Second, time.Minute},
+		},
+		{
+			desc: "rate limit reset",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": {"0"},
+					"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(2*time.Minute).Unix(), 10)},
+				},
+			},
+			expected: [2]time.Duration{118 * time.Second, 2 * time.Minute},
+		},
+		{
+			desc: "rate limit reset after the maximum wait",
+			resp: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": {"0"},
+					"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+				},
+			},
+			expected: [2]time.Duration{5 * time.Minute, 5 * time.Minute},
+		},
+		{
+			desc: "server error",
+			resp: &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Header:     http.Header{headerRetryAfter: {"60"}},
+			},
+			expected: [2]time.Duration{time.Second, time.Second},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			wait := githubBackoff(time.Second, 5*time.Minute, 0, test.resp)
+
+			assert.GreaterOrEqual(t, wait, test.expected[0])
+			assert.LessOrEqual(t, wait, test.expected[1])
+		})
+	}
+}
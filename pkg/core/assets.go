@@ -0,0 +1,395 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // JPEG decoder.
+	_ "image/png"  // PNG decoder.
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// Content types of the plugin images.
+const (
+	contentTypePNG  = "image/png"
+	contentTypeJPEG = "image/jpeg"
+	contentTypeSVG  = "image/svg+xml"
+)
+
+// assetSpec the constraints of a plugin image.
+type assetSpec struct {
+	name string
+	// maxSize the maximum size in bytes.
+	// The contents API doesn't return the content of the files larger than 1 MB.
+	maxSize   int
+	minWidth  int
+	minHeight int
+	maxWidth  int
+	maxHeight int
+}
+
+var (
+	iconSpec   = assetSpec{name: "icon", maxSize: 512 * 1024, minWidth: 32, minHeight: 32, maxWidth: 2048, maxHeight: 2048}
+	bannerSpec = assetSpec{name: "banner", maxSize: 1024 * 1024, minWidth: 320, minHeight: 80, maxWidth: 4096, maxHeight: 4096}
+)
+
+// svgForbiddenElements the SVG elements removed with their content.
+var svgForbiddenElements = []string{"script", "foreignobject", "iframe", "embed", "object", "handler", "listener", "style"}
+
+// asset a validated plugin image.
+type asset struct {
+	// URL the raw URL of the image in the repository.
+	URL         string
+	ContentType string
+	// Content the content of the image, the SVGs are sanitized.
+	// It is empty if the image has not been fetched, or if it exceeds the limits.
+	Content []byte
+	// Sanitized true if active content has been removed from the SVG: the repository URL must not be used.
+	Sanitized bool
+}
+
+// limitError an image exceeding the size or the dimension limits.
+// The image is still used: the limits are more recent than the published plugins.
+type limitError struct {
+	msg string
+}
+
+func (e limitError) Error() string {
+	return e.msg
+}
+
+// loadAsset fetches a plugin image at the analyzed version, and validates it.
+// The problems are reported as warnings, the asset is nil if the image is missing or invalid.
+// The URL of the image is kept if GitHub is unavailable, or if the image exceeds the size or the dimension limits.
+func (s *Scrapper) loadAsset(ctx context.Context, repository *github.Repository, version, imgPath string, spec assetSpec) (*asset, []Warning) {
+	if imgPath == "" {
+		return nil, nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, "scrapper_loadAsset_"+spec.name)
+	defer span.End()
+
+	imgURL := parseImageURL(repository, version, imgPath)
+	if imgURL == "" {
+		return nil, []Warning{newWarning(WarningAssetInvalid, "the %s %q must be a path of the repository, or a raw URL of the repository", spec.name, imgPath)}
+	}
+
+	ref, filePath, err := assetLocation(repository, version, imgURL)
+	if err != nil {
+		return nil, []Warning{newWarning(WarningAssetInvalid, "the %s %q: %v", spec.name, imgPath, err)}
+	}
+
+	opts := &github.RepositoryContentGetOptions{Ref: ref}
+
+	file, _, _, err := s.gh.Repositories.GetContents(ctx, repository.GetOwner().GetLogin(), repository.GetName(), filePath, opts)
+	if err != nil {
+		span.RecordError(err)
+
+		errResp := &github.ErrorResponse{}
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			return nil, []Warning{newWarning(WarningAssetMissing, "the %s %q doesn't exist at %s", spec.name, filePath, ref)}
+		}
+
+		return &asset{URL: imgURL}, []Warning{newWarning(WarningAssetUnavailable, "failed to get the %s %q: %v", spec.name, filePath, err)}
+	}
+
+	if file == nil {
+		return nil, []Warning{newWarning(WarningAssetInvalid, "the %s %q is not a file", spec.name, filePath)}
+	}
+
+	var warnings []Warning
+
+	overLimits := file.GetSize() > spec.maxSize
+	if overLimits {
+		warnings = append(warnings, newWarning(WarningAssetInvalid, "the %s %q is too large (%d bytes, maximum: %d bytes)", spec.name, filePath, file.GetSize(), spec.maxSize))
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		span.RecordError(err)
+		return &asset{URL: imgURL}, append(warnings, newWarning(WarningAssetUnavailable, "failed to get the content of the %s %q: %v", spec.name, filePath, err))
+	}
+
+	contentType, data, sanitized, err := validateImage([]byte(content), spec)
+	if err != nil {
+		invalid := newWarning(WarningAssetInvalid, "the %s %q is invalid: %v", spec.name, filePath, err)
+		if !errors.As(err, &limitError{}) {
+			return nil, []Warning{invalid}
+		}
+
+		overLimits = true
+		warnings = append(warnings, invalid)
+	}
+
+	if sanitized {
+		warnings = append(warnings, newWarning(WarningAssetSanitized, "the active content (scripts, event handlers, external references) of the %s %q has been removed", spec.name, filePath))
+	}
+
+	// The images exceeding the limits are not mirrored: the repository URL is kept, unless the SVG needed to be sanitized.
+	if overLimits {
+		return &asset{URL: imgURL, Sanitized: sanitized}, warnings
+	}
+
+	return &asset{URL: imgURL, ContentType: contentType, Content: data, Sanitized: sanitized}, warnings
+}
+
+// assetLocation returns the ref and the path of an image from its raw URL.
+func assetLocation(repository *github.Repository, version, imgURL string) (string, string, error) {
+	baseURL, err := rawContentURL(repository)
+	if err != nil {
+		return "", "", err
+	}
+
+	rawPrefixes := []string{
+		baseURL.String() + "/",
+		strings.TrimSuffix(repository.GetHTMLURL(), "/") + "/raw/",
+	}
+
+	for _, prefix := range rawPrefixes {
+		rest, found := strings.CutPrefix(imgURL, prefix)
+		if !found {
+			continue
+		}
+
+		rest, err = url.PathUnescape(rest)
+		if err != nil {
+			return "", "", err
+		}
+
+		ref, filePath, found := strings.Cut(rest, "/")
+		if !found || ref == "" || filePath == "" {
+			break
+		}
+
+		return ref, path.Clean(filePath), nil
+	}
+
+	return version, "", errors.New("unable to find the path of the image in the repository")
+}
+
+// validateImage checks the type and the dimensions of an image.
+// It returns the content type, and the content of the image (the SVGs are sanitized).
+// The dimension errors are limitErrors, returned with the content type and the content.
+func validateImage(content []byte, spec assetSpec) (string, []byte, bool, error) {
+	contentType := http.DetectContentType(content)
+
+	switch {
+	case contentType == contentTypePNG || contentType == contentTypeJPEG:
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(content))
+		if err != nil {
+			return "", nil, false, fmt.Errorf("failed to decode the image: %w", err)
+		}
+
+		return contentType, content, false, spec.checkDimensions(float64(cfg.Width), float64(cfg.Height))
+
+	case isSVG(content):
+		data, width, height, sanitized, err := sanitizeSVG(content)
+		if err != nil {
+			return "", nil, false, fmt.Errorf("failed to parse the SVG: %w", err)
+		}
+
+		// The SVGs without dimensions are scalable.
+		if width > 0 && height > 0 {
+			err = spec.checkDimensions(width, height)
+		}
+
+		return contentTypeSVG, data, sanitized, err
+
+	default:
+		return "", nil, false, fmt.Errorf("unsupported type %s (supported: PNG, JPEG, SVG)", contentType)
+	}
+}
+
+func (a assetSpec) checkDimensions(width, height float64) error {
+	if width < float64(a.minWidth) || height < float64(a.minHeight) {
+		return limitError{msg: fmt.Sprintf("the image is too small (%gx%g, minimum: %dx%d)", width, height, a.minWidth, a.minHeight)}
+	}
+
+	if width > float64(a.maxWidth) || height > float64(a.maxHeight) {
+		return limitError{msg: fmt.Sprintf("the image is too large (%gx%g, maximum: %dx%d)", width, height, a.maxWidth, a.maxHeight)}
+	}
+
+	return nil
+}
+
+func isSVG(content []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		token, err := decoder.RawToken()
+		if err != nil {
+			return false
+		}
+
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "svg"
+		}
+	}
+}
+
+// sanitizeSVG removes the active content of an SVG: the scripts and the embedded documents, the event handlers,
+// the external references, the comments and the directives (ex: DOCTYPE and entities).
+// It returns the sanitized SVG, its dimensions (0 if unknown), and true if some content has been removed.
+func sanitizeSVG(content []byte) ([]byte, float64, float64, bool, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var buf bytes.Buffer
+	var width, height float64
+	var sanitized bool
+
+	// skip the depth inside a forbidden element.
+	skip := 0
+	root := true
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, 0, false, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if skip > 0 || isForbiddenSVGElement(t.Name) {
+				skip++
+				sanitized = true
+				continue
+			}
+
+			if root {
+				width, height = svgDimensions(t)
+				root = false
+			}
+
+			buf.WriteString("<" + qualifiedName(t.Name))
+
+			for _, attr := range t.Attr {
+				if !isAllowedSVGAttr(attr) {
+					sanitized = true
+					continue
+				}
+
+				buf.WriteString(" " + qualifiedName(attr.Name) + `="`)
+				_ = xml.EscapeText(&buf, []byte(attr.Value))
+				buf.WriteString(`"`)
+			}
+
+			buf.WriteString(">")
+
+		case xml.EndElement:
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			buf.WriteString("</" + qualifiedName(t.Name) + ">")
+
+		case xml.CharData:
+			if skip > 0 {
+				continue
+			}
+
+			_ = xml.EscapeText(&buf, t)
+
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				buf.WriteString("<?xml " + string(t.Inst) + "?>")
+			}
+
+		case xml.Directive, xml.Comment:
+			// The directives and the comments are removed.
+		}
+	}
+
+	return buf.Bytes(), width, height, sanitized, nil
+}
+
+func isForbiddenSVGElement(name xml.Name) bool {
+	for _, forbidden := range svgForbiddenElements {
+		if strings.EqualFold(name.Local, forbidden) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isAllowedSVGAttr(attr xml.Attr) bool {
+	name := strings.ToLower(attr.Name.Local)
+
+	if strings.HasPrefix(name, "on") {
+		return false
+	}
+
+	if name == "href" || name == "src" {
+		value := strings.TrimSpace(attr.Value)
+		return strings.HasPrefix(value, "#") || strings.HasPrefix(value, "data:image/png") || strings.HasPrefix(value, "data:image/jpeg")
+	}
+
+	value := strings.ToLower(attr.Value)
+
+	// The CSS can load external resources, the escapes could hide them.
+	if name == "style" && (strings.Contains(value, "url(") || strings.Contains(value, "@import") || strings.Contains(value, `\`)) {
+		return false
+	}
+
+	return !strings.Contains(value, "javascript:")
+}
+
+func qualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+// svgDimensions returns the dimensions of the SVG from the width and height attributes, or from the viewBox.
+func svgDimensions(root xml.StartElement) (float64, float64) {
+	var width, height float64
+	var viewBox string
+
+	for _, attr := range root.Attr {
+		switch attr.Name.Local {
+		case "width":
+			width = parseSVGLength(attr.Value)
+		case "height":
+			height = parseSVGLength(attr.Value)
+		case "viewBox":
+			viewBox = attr.Value
+		}
+	}
+
+	if width > 0 && height > 0 {
+		return width, height
+	}
+
+	fields := strings.FieldsFunc(viewBox, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) != 4 {
+		return 0, 0
+	}
+
+	return parseSVGLength(fields[2]), parseSVGLength(fields[3])
+}
+
+// parseSVGLength parses a length in pixels, it returns 0 for the other units.
+func parseSVGLength(value string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "px"), 64)
+	if err != nil {
+		return 0
+	}
+
+	return v
+}
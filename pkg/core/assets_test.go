@@ -0,0 +1,347 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/fakegithub"
+)
+
+func Test_validateImage(t *testing.T) {
+	testCases := []struct {
+		desc                string
+		content             []byte
+		expectedContentType string
+		expectedSanitized   bool
+		expectedErr         string
+		expectedLimit       bool
+	}{
+		{
+			desc:                "PNG",
+			content:             encodeImage(t, png.Encode, 64, 64),
+			expectedContentType: "image/png",
+		},
+		{
+			desc:                "JPEG",
+			content:             encodeImage(t, func(w io.Writer, img image.Image) error { return jpeg.Encode(w, img, nil) }, 64, 64),
+			expectedContentType: "image/jpeg",
+		},
+		{
+			desc:                "SVG",
+			content:             []byte(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64"><circle cx="32" cy="32" r="16"/></svg>`),
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			desc:                "SVG without dimensions",
+			content:             []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"><circle cx="32" cy="32" r="16"/></svg>`),
+			expectedContentType: "image/svg+xml",
+		},
+		{
+			desc:                "SVG with script",
+			content:             []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64"><script>alert(1)</script></svg>`),
+			expectedContentType: "image/svg+xml",
+			expectedSanitized:   true,
+		},
+		{
+			desc:          "too small PNG",
+			content:       encodeImage(t, png.Encode, 16, 16),
+			expectedErr:   "the image is too small (16x16, minimum: 32x32)",
+			expectedLimit: true,
+		},
+		{
+			desc:          "too large SVG",
+			content:       []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="4096px" height="64px"></svg>`),
+			expectedErr:   "the image is too large (4096x64, maximum: 2048x2048)",
+			expectedLimit: true,
+		},
+		{
+			desc:        "GIF",
+			content:     encodeImage(t, func(w io.Writer, img image.Image) error { return gif.Encode(w, img, nil) }, 64, 64),
+			expectedErr: "unsupported type image/gif (supported: PNG, JPEG, SVG)",
+		},
+		{
+			desc:        "truncated PNG",
+			content:     encodeImage(t, png.Encode, 64, 64)[:20],
+			expectedErr: "failed to decode the image: unexpected EOF",
+		},
+		{
+			desc:        "not an image",
+			content:     []byte("# README"),
+			expectedErr: "unsupported type text/plain; charset=utf-8 (supported: PNG, JPEG, SVG)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			contentType, data, sanitized, err := validateImage(test.content, iconSpec)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				assert.Equal(t, test.expectedLimit, errors.As(err, &limitError{}))
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedContentType, contentType)
+			assert.Equal(t, test.expectedSanitized, sanitized)
+			assert.NotEmpty(t, data)
+		})
+	}
+}
+
+func Test_sanitizeSVG(t *testing.T) {
+	testCases := []struct {
+		desc              string
+		content           string
+		expected          string
+		expectedSanitized bool
+	}{
+		{
+			desc:     "clean",
+			content:  `<?xml version="1.0" encoding="UTF-8"?><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10"><defs><path id="a" d="M0 0h10v10H0z"/></defs><use xlink:href="#a" style="fill: red"/></svg>`,
+			expected: `<?xml version="1.0" encoding="UTF-8"?><svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 10 10"><defs><path id="a" d="M0 0h10v10H0z"></path></defs><use xlink:href="#a" style="fill: red"></use></svg>`,
+		},
+		{
+			desc:              "script",
+			content:           `<svg><script type="text/javascript"><![CDATA[alert(1)]]></script><g><script>alert(2)</script></g></svg>`,
+			expected:          `<svg><g></g></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:              "foreignObject",
+			content:           `<svg><foreignObject><iframe src="https://example.com"></iframe></foreignObject><rect/></svg>`,
+			expected:          `<svg><rect></rect></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:              "event handlers",
+			content:           `<svg onload="alert(1)"><rect onclick="alert(2)" width="10"/></svg>`,
+			expected:          `<svg><rect width="10"></rect></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:              "external references",
+			content:           `<svg><a href="javascript:alert(1)"><image xlink:href="https://example.com/tracker.png"/></a><image href="data:image/png;base64,AAAA"/></svg>`,
+			expected:          `<svg><a><image></image></a><image href="data:image/png;base64,AAAA"></image></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:              "style element",
+			content:           `<svg><style>@import url(https://example.com/tracker.css); rect { fill: url(https://example.com/a.png) }</style><rect/></svg>`,
+			expected:          `<svg><rect></rect></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:              "style attributes",
+			content:           `<svg><rect style="fill: URL(https://example.com/a.png)"/><rect style="@import 'https://example.com/a.css'"/><rect style="fill: u\72l(https://example.com/a.png)"/><rect style="fill: red" fill="url(#gradient)"/></svg>`,
+			expected:          `<svg><rect></rect><rect></rect><rect></rect><rect style="fill: red" fill="url(#gradient)"></rect></svg>`,
+			expectedSanitized: true,
+		},
+		{
+			desc:     "doctype and comments",
+			content:  `<!DOCTYPE svg PUBLIC "-//W3C//DTD SVG 1.1//EN" "http://www.w3.org/Graphics/SVG/1.1/DTD/svg11.dtd"><!-- Generator --><svg></svg>`,
+			expected: `<svg></svg>`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			data, _, _, sanitized, err := sanitizeSVG([]byte(test.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, string(data))
+			assert.Equal(t, test.expectedSanitized, sanitized)
+		})
+	}
+}
+
+func Test_sanitizeSVG_entity(t *testing.T) {
+	content := `<!DOCTYPE svg [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><svg><text>&xxe;</text></svg>`
+
+	_, _, _, _, err := sanitizeSVG([]byte(content))
+	assert.Error(t, err)
+}
+
+func Test_assetLocation(t *testing.T) {
+	repo := &github.Repository{
+		Owner:   &github.User{Login: github.String("traefik")},
+		Name:    github.String("plugintest"),
+		HTMLURL: github.String("https://github.com/traefik/plugintest"),
+	}
+
+	testCases := []struct {
+		desc         string
+		imgURL       string
+		expectedRef  string
+		expectedPath string
+	}{
+		{
+			desc:         "raw.githubusercontent.com",
+			imgURL:       "https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/.assets/icon.png",
+			expectedRef:  "v1.0.0",
+			expectedPath: ".assets/icon.png",
+		},
+		{
+			desc:         "raw path",
+			imgURL:       "https://github.com/traefik/plugintest/raw/main/img/my%20icon.png",
+			expectedRef:  "main",
+			expectedPath: "img/my icon.png",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			ref, filePath, err := assetLocation(repo, "v1.0.0", test.imgURL)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedRef, ref)
+			assert.Equal(t, test.expectedPath, filePath)
+		})
+	}
+}
+
+func TestScrapper_loadAsset(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string][]byte{
+		"icon.png":         encodeImage(t, png.Encode, 64, 64),
+		"small.png":        encodeImage(t, png.Encode, 16, 16),
+		"large.png":        append(encodeImage(t, png.Encode, 64, 64), make([]byte, iconSpec.maxSize)...),
+		"script.svg":       []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64"><script>alert(1)</script></svg>`),
+		"small-script.svg": []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16"><script>alert(1)</script></svg>`),
+		"readme.md":        []byte("# README"),
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0o600))
+	}
+
+	fake, err := fakegithub.NewServer(fakegithub.Repository{
+		Owner: "traefik",
+		Name:  "plugintest",
+		Tags:  []fakegithub.Tag{{Name: "v0.1.0", Dir: dir}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(fake.Close)
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, err = url.Parse(fake.URL())
+	require.NoError(t, err)
+
+	repository := &github.Repository{
+		Owner:   &github.User{Login: github.String("traefik")},
+		Name:    github.String("plugintest"),
+		HTMLURL: github.String(fake.URL() + "traefik/plugintest"),
+	}
+
+	rawURL := fake.URL() + "traefik/plugintest/raw/v0.1.0/"
+
+	testCases := []struct {
+		desc              string
+		path              string
+		expectedURL       string
+		expectedContent   bool
+		expectedSanitized bool
+		expectedWarnings  []WarningCode
+	}{
+		{
+			desc:            "valid",
+			path:            "icon.png",
+			expectedURL:     rawURL + "icon.png",
+			expectedContent: true,
+		},
+		{
+			desc:             "missing",
+			path:             "missing.png",
+			expectedWarnings: []WarningCode{WarningAssetMissing},
+		},
+		{
+			desc:             "not an image",
+			path:             "readme.md",
+			expectedWarnings: []WarningCode{WarningAssetInvalid},
+		},
+		{
+			desc:             "too small",
+			path:             "small.png",
+			expectedURL:      rawURL + "small.png",
+			expectedWarnings: []WarningCode{WarningAssetInvalid},
+		},
+		{
+			desc:             "too large",
+			path:             "large.png",
+			expectedURL:      rawURL + "large.png",
+			expectedWarnings: []WarningCode{WarningAssetInvalid},
+		},
+		{
+			desc:              "sanitized",
+			path:              "script.svg",
+			expectedURL:       rawURL + "script.svg",
+			expectedContent:   true,
+			expectedSanitized: true,
+			expectedWarnings:  []WarningCode{WarningAssetSanitized},
+		},
+		{
+			desc:              "sanitized and too small",
+			path:              "small-script.svg",
+			expectedURL:       rawURL + "small-script.svg",
+			expectedSanitized: true,
+			expectedWarnings:  []WarningCode{WarningAssetInvalid, WarningAssetSanitized},
+		},
+	}
+
+	scrapper := NewScrapper(ghClient, nil, nil, false, nil, nil, nil)
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			a, warnings := scrapper.loadAsset(context.Background(), repository, "v0.1.0", test.path, iconSpec)
+
+			var codes []WarningCode
+			for _, warning := range warnings {
+				codes = append(codes, warning.Code)
+			}
+
+			assert.Equal(t, test.expectedWarnings, codes)
+
+			if test.expectedURL == "" {
+				assert.Nil(t, a)
+				return
+			}
+
+			require.NotNil(t, a)
+			assert.Equal(t, test.expectedURL, a.URL)
+			assert.Equal(t, test.expectedContent, len(a.Content) > 0)
+			assert.Equal(t, test.expectedSanitized, a.Sanitized)
+		})
+	}
+}
+
+func encodeImage(t *testing.T, encode func(w io.Writer, img image.Image) error, width, height int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
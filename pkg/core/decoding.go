@@ -0,0 +1,202 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/traefik/paerser/parser"
+)
+
+// Decoding modes of the plugin configuration.
+const (
+	// DecodingMapstructure decodes the test data of the manifest.
+	DecodingMapstructure = "mapstructure"
+	// DecodingTraefik also decodes the test data as Traefik receives it from the labels and the Kubernetes CRDs,
+	// and reports the fields decoded differently.
+	DecodingTraefik = "traefik"
+)
+
+// traefikConfigInputs returns the plugin configurations passed by Traefik to the decoder, by source.
+// The configuration from a file is the test data itself: the manifest is decoded like the file provider does.
+// The labels are not a source if the test data cannot be flattened (the snippets are omitted with a warning).
+func traefikConfigInputs(manifest Manifest) (map[string]map[string]interface{}, error) {
+	inputs := make(map[string]map[string]interface{})
+
+	fromLabels, err := labelsConfigInput(manifest)
+	if err != nil && !errors.As(err, &labelKeyError{}) {
+		return nil, err
+	}
+	if err == nil {
+		inputs["labels"] = fromLabels
+	}
+
+	// The Kubernetes CRDs are decoded as JSON: the scalar types of the YAML are kept, the numbers are float64.
+	raw := manifest.rawTestData
+	if raw == nil {
+		raw = manifest.TestData
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the test data (JSON): %w", err)
+	}
+
+	fromCRD := map[string]interface{}{}
+	err = json.Unmarshal(data, &fromCRD)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the test data (JSON): %w", err)
+	}
+
+	inputs["kubernetes"] = fromCRD
+
+	return inputs, nil
+}
+
+// labelsConfigInput returns the plugin configuration decoded from the labels by paerser: all the values are strings.
+func labelsConfigInput(manifest Manifest) (map[string]interface{}, error) {
+	labels, err := flattenTestData(labelRoot+".http.middlewares.test.plugin.test", manifest.TestData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to flatten the test data: %w", err)
+	}
+
+	var cfg dynamicConfig
+	if len(labels) > 0 {
+		err = parser.Decode(labels, &cfg, labelRoot, labelRoot+".http")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the labels: %w", err)
+		}
+	}
+
+	fromLabels := map[string]interface{}(cfg.plugin("test", "test"))
+	if fromLabels == nil {
+		fromLabels = map[string]interface{}{}
+	}
+
+	return fromLabels, nil
+}
+
+// checkTraefikDecoding decodes the Traefik inputs of the test data into new configurations,
+// and compares them with the configuration decoded from the test data.
+// A configuration that cannot be decoded is an error, a field decoded differently is a warning.
+func checkTraefikDecoding(manifest Manifest, vConfig reflect.Value, createConfig func() (reflect.Value, error)) ([]Warning, error) {
+	inputs, err := traefikConfigInputs(manifest)
+	if err != nil {
+		return nil, newError(CodeYaegiTestData, err)
+	}
+
+	var warnings []Warning
+
+	for _, source := range sortedKeys(inputs) {
+		vSourceConfig, err := createConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		err = decodeConfig(vSourceConfig, inputs[source])
+		if err != nil {
+			return nil, newError(CodeYaegiTestData, fmt.Errorf("from %s: %w", source, err))
+		}
+
+		for _, diff := range diffConfigValues("Config", vConfig, vSourceConfig) {
+			warnings = append(warnings, newWarning(WarningConfigDecoding, "%s from %s", diff, source))
+		}
+	}
+
+	return warnings, nil
+}
+
+// diffConfigValues compares the decoded configurations field by field.
+func diffConfigValues(name string, expected, actual reflect.Value) []string {
+	for expected.Kind() == reflect.Pointer || expected.Kind() == reflect.Interface {
+		if expected.IsNil() {
+			break
+		}
+		expected = expected.Elem()
+	}
+
+	for actual.Kind() == reflect.Pointer || actual.Kind() == reflect.Interface {
+		if actual.IsNil() {
+			break
+		}
+		actual = actual.Elem()
+	}
+
+	if expected.Kind() != actual.Kind() || isEmptyValue(expected) || isEmptyValue(actual) {
+		if isEmptyValue(expected) && isEmptyValue(actual) {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("%s is %s instead of %s", name, formatValue(actual), formatValue(expected))}
+	}
+
+	switch expected.Kind() {
+	case reflect.Struct:
+		var diffs []string
+		for i := range expected.NumField() {
+			field := expected.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			diffs = append(diffs, diffConfigValues(name+"."+field.Name, expected.Field(i), actual.Field(i))...)
+		}
+
+		return diffs
+
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, key := range append(expected.MapKeys(), actual.MapKeys()...) {
+			keys[fmt.Sprint(key.Interface())] = key
+		}
+
+		var diffs []string
+		for _, k := range sortedKeys(keys) {
+			diffs = append(diffs, diffConfigValues(fmt.Sprintf("%s[%s]", name, k), expected.MapIndex(keys[k]), actual.MapIndex(keys[k]))...)
+		}
+
+		return diffs
+
+	case reflect.Slice, reflect.Array:
+		if expected.Len() != actual.Len() {
+			return []string{fmt.Sprintf("%s is %s instead of %s", name, formatValue(actual), formatValue(expected))}
+		}
+
+		var diffs []string
+		for i := range expected.Len() {
+			diffs = append(diffs, diffConfigValues(fmt.Sprintf("%s[%d]", name, i), expected.Index(i), actual.Index(i))...)
+		}
+
+		return diffs
+
+	default:
+		if reflect.DeepEqual(expected.Interface(), actual.Interface()) {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("%s is %s instead of %s", name, formatValue(actual), formatValue(expected))}
+	}
+}
+
+// isEmptyValue returns true for the nil values, and the empty maps and slices.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
+
+func formatValue(v reflect.Value) string {
+	if isEmptyValue(v) {
+		return "empty"
+	}
+
+	return fmt.Sprintf("%#v", v.Interface())
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pfile "github.com/traefik/paerser/file"
+	"gopkg.in/yaml.v3"
+)
+
+type decodingConfig struct {
+	Name     string
+	Ratio    string
+	Enabled  bool
+	Count    int
+	Values   []string
+	Headers  map[string]string
+	Sub      *decodingSubConfig
+	internal string
+}
+
+type decodingSubConfig struct {
+	Timeout int
+}
+
+func Test_checkTraefikDecoding(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		testData string
+		expected []Warning
+		errCode  Code
+	}{
+		{
+			desc: "same configuration",
+			testData: `
+Name: foo
+Enabled: true
+Count: 2
+Values: [a, b]
+Headers:
+  X-Foo: bar
+Sub:
+  Timeout: 10
+`,
+		},
+		{
+			desc: "number decoded as a float from the CRDs",
+			testData: `
+Ratio: 1.5
+`,
+			expected: []Warning{
+				{Code: WarningConfigDecoding, Message: `Config.Ratio is "1.5" instead of "1.500000" from kubernetes`},
+			},
+		},
+		{
+			desc: "comma-separated values decoded as a list from the labels",
+			testData: `
+Values: "a,b"
+`,
+			expected: nil,
+		},
+		{
+			desc: "key which cannot be flattened in the labels",
+			testData: `
+Headers:
+  X.Foo: bar
+`,
+			expected: nil,
+		},
+		{
+			desc: "invalid type from the CRDs",
+			testData: `
+Count: [1, 2]
+`,
+			errCode: CodeYaegiTestData,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			manifest := loadTestDataManifest(t, test.testData)
+
+			createConfig := func() (reflect.Value, error) {
+				return reflect.ValueOf(&decodingConfig{internal: "x"}), nil
+			}
+
+			vConfig, err := createConfig()
+			require.NoError(t, err)
+
+			err = decodeConfig(vConfig, manifest.TestData)
+			if err != nil {
+				require.NotEmpty(t, test.errCode)
+				return
+			}
+
+			warnings, err := checkTraefikDecoding(manifest, vConfig, createConfig)
+			if test.errCode != "" {
+				require.Error(t, err)
+				assert.Equal(t, test.errCode, AsError(err).Code)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, warnings)
+		})
+	}
+}
+
+func Test_diffConfigValues(t *testing.T) {
+	expected := &decodingConfig{
+		Name:    "foo",
+		Values:  []string{"a", "b"},
+		Headers: map[string]string{"X-Foo": "bar"},
+		Sub:     &decodingSubConfig{Timeout: 10},
+	}
+
+	actual := &decodingConfig{
+		Name:    "foo",
+		Values:  []string{"a,b"},
+		Headers: map[string]string{"X-Foo": "baz", "X-Bar": "bar"},
+	}
+
+	diffs := diffConfigValues("Config", reflect.ValueOf(expected), reflect.ValueOf(actual))
+
+	assert.Equal(t, []string{
+		`Config.Values is []string{"a,b"} instead of []string{"a", "b"}`,
+		`Config.Headers[X-Bar] is "bar" instead of empty`,
+		`Config.Headers[X-Foo] is "baz" instead of "bar"`,
+		`Config.Sub is empty instead of core.decodingSubConfig{Timeout:10}`,
+	}, diffs)
+}
+
+// loadTestDataManifest decodes the test data like loadManifestContent.
+func loadTestDataManifest(t *testing.T, testData string) Manifest {
+	t.Helper()
+
+	content := "testData:\n" + indent(testData)
+
+	var manifest Manifest
+	err := yaml.Unmarshal([]byte(content), &manifest)
+	require.NoError(t, err)
+
+	var mp Manifest
+	err = pfile.DecodeContent(content, ".yaml", &mp)
+	require.NoError(t, err)
+
+	manifest.rawTestData = manifest.TestData
+	manifest.TestData = mp.TestData
+
+	return manifest
+}
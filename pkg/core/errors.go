@@ -0,0 +1,184 @@
+package core
+
+import (
+	"errors"
+	"net"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/ldez/grignotin/goproxy"
+)
+
+// Category the category of an analysis error.
+type Category string
+
+// Categories of the analysis errors.
+const (
+	CategoryManifest       Category = "manifest"
+	CategoryModule         Category = "module"
+	CategoryVersioning     Category = "versioning"
+	CategorySources        Category = "sources"
+	CategoryYaegi          Category = "yaegi"
+	CategoryWASM           Category = "wasm"
+	CategoryInfrastructure Category = "infrastructure"
+	// CategoryUnknown an error not classified yet.
+	CategoryUnknown Category = "unknown"
+)
+
+// Code the stable identifier of an analysis error.
+type Code string
+
+// Codes of the analysis errors.
+const (
+	CodeManifestMissing         Code = "manifest_missing"
+	CodeManifestInvalid         Code = "manifest_invalid"
+	CodeManifestUnsupportedType Code = "manifest_unsupported_type"
+	CodeManifestMissingField    Code = "manifest_missing_field"
+	CodeManifestSnippet         Code = "manifest_snippet_mismatch"
+
+	CodeModuleMissing        Code = "module_missing"
+	CodeModuleInvalid        Code = "module_invalid"
+	CodeModuleInvalidName    Code = "module_invalid_name"
+	CodeModuleInvalidImport  Code = "module_invalid_import"
+	CodeModuleForbiddenDep   Code = "module_forbidden_dependency"
+	CodeModuleUnknownVersion Code = "module_unknown_version"
+
+	CodeVersionMissingTag Code = "version_missing_tag"
+	CodeVersionInvalidTag Code = "version_invalid_tag"
+
+	CodeSourcesUnavailable Code = "sources_unavailable"
+	CodeReadmeUnavailable  Code = "readme_unavailable"
+
+	CodeYaegiLoad         Code = "yaegi_load"
+	CodeYaegiCreateConfig Code = "yaegi_create_config"
+	CodeYaegiTestData     Code = "yaegi_test_data"
+	CodeYaegiNewSignature Code = "yaegi_new_signature"
+	CodeYaegiNewFailed    Code = "yaegi_new_failed"
+
+	CodeWASMRelease Code = "wasm_release"
+	CodeWASMArchive Code = "wasm_archive"
+	CodeWASMRun     Code = "wasm_run"
+
+	CodeGitHubUnavailable Code = "github_unavailable"
+	CodeSearchFailed      Code = "search_failed"
+	CodeStoreFailed       Code = "store_failed"
+	CodeInternal          Code = "internal"
+
+	// CodeUnknown an error not classified yet, it's reported to the plugin author.
+	CodeUnknown Code = "unknown"
+)
+
+var codeCategories = map[Code]Category{
+	CodeManifestMissing:         CategoryManifest,
+	CodeManifestInvalid:         CategoryManifest,
+	CodeManifestUnsupportedType: CategoryManifest,
+	CodeManifestMissingField:    CategoryManifest,
+	CodeManifestSnippet:         CategoryManifest,
+
+	CodeModuleMissing:        CategoryModule,
+	CodeModuleInvalid:        CategoryModule,
+	CodeModuleInvalidName:    CategoryModule,
+	CodeModuleInvalidImport:  CategoryModule,
+	CodeModuleForbiddenDep:   CategoryModule,
+	CodeModuleUnknownVersion: CategoryModule,
+
+	CodeVersionMissingTag: CategoryVersioning,
+	CodeVersionInvalidTag: CategoryVersioning,
+
+	CodeSourcesUnavailable: CategorySources,
+	CodeReadmeUnavailable:  CategorySources,
+
+	CodeYaegiLoad:         CategoryYaegi,
+	CodeYaegiCreateConfig: CategoryYaegi,
+	CodeYaegiTestData:     CategoryYaegi,
+	CodeYaegiNewSignature: CategoryYaegi,
+	CodeYaegiNewFailed:    CategoryYaegi,
+
+	CodeWASMRelease: CategoryWASM,
+	CodeWASMArchive: CategoryWASM,
+	CodeWASMRun:     CategoryWASM,
+
+	CodeGitHubUnavailable: CategoryInfrastructure,
+	CodeSearchFailed:      CategoryInfrastructure,
+	CodeStoreFailed:       CategoryInfrastructure,
+	CodeInternal:          CategoryInfrastructure,
+
+	CodeUnknown: CategoryUnknown,
+}
+
+// Error an analysis error.
+// The message is the message of the cause.
+type Error struct {
+	Code     Code
+	Category Category
+	// Version the analyzed version, empty if unknown.
+	Version string
+	Err     error
+}
+
+// newError wraps a cause with a code.
+func newError(code Code, err error) *Error {
+	return &Error{Code: code, Category: codeCategories[code], Err: err}
+}
+
+// newFetchError wraps the cause of a failed call to GitHub or to the Go proxy with a code.
+// An unavailability (ex: 5xx, rate limit) takes precedence over the code.
+func newFetchError(code Code, err error) *Error {
+	if isUnavailable(err) {
+		code = CodeGitHubUnavailable
+	}
+
+	return newError(code, err)
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Reportable returns true if the error must be reported to the plugin author (issue).
+// The infrastructure errors are not related to the plugin.
+func (e *Error) Reportable() bool {
+	return e.Category != CategoryInfrastructure
+}
+
+// AsError returns the analysis error of err.
+// An unclassified error is an infrastructure error if it comes from GitHub unavailability, CodeUnknown otherwise.
+func AsError(err error) *Error {
+	var aErr *Error
+	if errors.As(err, &aErr) {
+		return aErr
+	}
+
+	if isUnavailable(err) {
+		return newError(CodeGitHubUnavailable, err)
+	}
+
+	return newError(CodeUnknown, err)
+}
+
+// isUnavailable returns true if the error is a server error (5xx) of GitHub or of the Go proxy, a rate limit, or a network error.
+func isUnavailable(err error) bool {
+	errResp := &github.ErrorResponse{}
+	if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+
+	proxyErr := &goproxy.APIError{}
+	if errors.As(err, &proxyErr) && proxyErr.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+
+	rateLimitErr := &github.RateLimitError{}
+	abuseErr := &github.AbuseRateLimitError{}
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr)
+}
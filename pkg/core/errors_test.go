@@ -0,0 +1,106 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/ldez/grignotin/goproxy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsError(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		err              error
+		expectedCode     Code
+		expectedCategory Category
+		reportable       bool
+	}{
+		{
+			desc:             "classified",
+			err:              newError(CodeVersionInvalidTag, errors.New("invalid tag: 1.0")),
+			expectedCode:     CodeVersionInvalidTag,
+			expectedCategory: CategoryVersioning,
+			reportable:       true,
+		},
+		{
+			desc:             "wrapped",
+			err:              fmt.Errorf("failed to get the latest tag: %w", newError(CodeVersionMissingTag, errors.New("missing tag/version"))),
+			expectedCode:     CodeVersionMissingTag,
+			expectedCategory: CategoryVersioning,
+			reportable:       true,
+		},
+		{
+			desc:             "fetch error",
+			err:              newFetchError(CodeManifestMissing, githubError(http.StatusNotFound)),
+			expectedCode:     CodeManifestMissing,
+			expectedCategory: CategoryManifest,
+			reportable:       true,
+		},
+		{
+			desc:             "fetch error, GitHub unavailable",
+			err:              newFetchError(CodeManifestMissing, githubError(http.StatusBadGateway)),
+			expectedCode:     CodeGitHubUnavailable,
+			expectedCategory: CategoryInfrastructure,
+		},
+		{
+			desc:             "unclassified, GitHub unavailable",
+			err:              fmt.Errorf("failed: %w", githubError(http.StatusInternalServerError)),
+			expectedCode:     CodeGitHubUnavailable,
+			expectedCategory: CategoryInfrastructure,
+		},
+		{
+			desc:             "fetch error, Go proxy unavailable",
+			err:              newFetchError(CodeSourcesUnavailable, fmt.Errorf("failed to get archive: %w", &goproxy.APIError{StatusCode: http.StatusServiceUnavailable})),
+			expectedCode:     CodeGitHubUnavailable,
+			expectedCategory: CategoryInfrastructure,
+		},
+		{
+			desc:             "fetch error, Go proxy not found",
+			err:              newFetchError(CodeSourcesUnavailable, fmt.Errorf("failed to get archive: %w", &goproxy.APIError{StatusCode: http.StatusNotFound})),
+			expectedCode:     CodeSourcesUnavailable,
+			expectedCategory: CategorySources,
+			reportable:       true,
+		},
+		{
+			desc:             "unclassified, rate limit",
+			err:              &github.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}},
+			expectedCode:     CodeGitHubUnavailable,
+			expectedCategory: CategoryInfrastructure,
+		},
+		{
+			desc:             "unclassified",
+			err:              errors.New("boom"),
+			expectedCode:     CodeUnknown,
+			expectedCategory: CategoryUnknown,
+			reportable:       true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			aErr := AsError(test.err)
+
+			assert.Equal(t, test.expectedCode, aErr.Code)
+			assert.Equal(t, test.expectedCategory, aErr.Category)
+			assert.Equal(t, test.reportable, aErr.Reportable())
+		})
+	}
+}
+
+func TestError_message(t *testing.T) {
+	cause := errors.New("missing DisplayName")
+	err := newError(CodeManifestMissingField, cause)
+
+	assert.EqualError(t, err, "missing DisplayName")
+	assert.ErrorIs(t, err, cause)
+}
+
+func githubError(statusCode int) error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: statusCode, Request: &http.Request{}}}
+}
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxIssueBodySize the maximum size of an issue body accepted by GitHub.
+const maxIssueBodySize = 65536
+
+const docsURL = "https://plugins.traefik.io/create"
+
+const truncatedMarker = "\n[... %d characters truncated ...]\n"
+
+//go:embed issue.md.tmpl
+var defaultIssueTemplate string
+
+var remediations = map[Code]string{
+	CodeManifestMissing: "Add a `.traefik.yml` manifest at the root of the repository, for the tagged version.",
+	CodeManifestInvalid: "Fix the syntax of the `.traefik.yml` manifest, the `testData` must be a valid configuration of the plugin.",
+	CodeManifestUnsupportedType: "Set the `type` of the `.traefik.yml` manifest to `middleware`." +
+		" The `provider` type is only supported by the Yaegi plugins.",
+	CodeManifestMissingField: "Add the missing field (`import`, `displayName`, `summary`, `testData`) to the `.traefik.yml` manifest.",
+	CodeManifestSnippet: "The `testData` of the `.traefik.yml` manifest must be decoded by Traefik to the same configuration" +
+		" from a file, labels, tags and CLI flags: the keys must not contain dots or brackets, and must not differ only by their case.",
+	CodeModuleMissing:        "Add a `go.mod` file at the root of the repository, for the tagged version.",
+	CodeModuleInvalid:        "Fix the `go.mod` file, `go mod tidy` must succeed.",
+	CodeModuleInvalidName:    "The module name and the `import` of the `.traefik.yml` manifest must contain the name of the GitHub repository.",
+	CodeModuleInvalidImport:  "The `import` of the `.traefik.yml` manifest must be the module name, or a package of the module.",
+	CodeModuleForbiddenDep:   "Remove the dependency to Traefik or Yaegi: a plugin is interpreted by Traefik and cannot import them.",
+	CodeModuleUnknownVersion: "The tag is not available on the Go module proxy yet, or the module name doesn't match the repository.",
+	CodeVersionMissingTag:    "Create a tag following the semantic versioning (ex: `v1.0.0`), the plugins are imported from their latest tag.",
+	CodeVersionInvalidTag: "Remove the tags that don't follow the semantic versioning (ex: `git push --delete origin 1.0`)," +
+		" and create a new tag like `v1.0.1`.",
+	CodeYaegiLoad: "The dependencies of a plugin must be vendored: run `go mod vendor` and commit the `vendor` directory." +
+		" The packages `unsafe` and `syscall` are not allowed.",
+	CodeYaegiCreateConfig: "The package must provide a function `CreateConfig() *Config`, without error at runtime.",
+	CodeYaegiTestData:     "The `testData` of the `.traefik.yml` manifest must match the fields of the `Config` struct.",
+	CodeYaegiNewSignature: "The signature of the function `New` must be" +
+		" `func New(ctx context.Context, next http.Handler, config *Config, name string) (http.Handler, error)`.",
+	CodeYaegiNewFailed: "The function `New` must succeed with the `testData` of the `.traefik.yml` manifest." +
+		" It must not depend on files, environment variables or network resources not available at the creation.",
+	CodeWASMRelease: "A WASM plugin must be published as a GitHub release containing a single zip archive (`plugin.wasm` and `.traefik.yml`).",
+	CodeWASMArchive: "The zip archive of the release must contain `plugin.wasm` and `.traefik.yml` at its root.",
+	CodeWASMRun:     "The `plugin.wasm` must be instantiated by Traefik with the `testData` of the `.traefik.yml` manifest.",
+}
+
+// issueData the data passed to the issue template.
+type issueData struct {
+	Code     Code
+	Category Category
+	// Remediation the hint to fix the error, empty if unknown.
+	Remediation string
+	// Version the analyzed version, empty if unknown.
+	Version string
+	DocsURL string
+	// Log the redacted cause, truncated to fit in the issue body.
+	Log string
+	// LogFence the code fence around the log, longer than any backtick run of the log.
+	LogFence string
+}
+
+// ParseIssueTemplate parses a Go text/template file used to render the body of the analyzer issues.
+// The template is also executed with sample data, to detect the execution errors (ex: unknown fields) at startup.
+func ParseIssueTemplate(filename string) (*template.Template, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue template: %w", err)
+	}
+
+	tmpl, err := template.New("issue").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issue template: %w", err)
+	}
+
+	sample := issueData{
+		Code:        CodeYaegiLoad,
+		Category:    CategoryYaegi,
+		Remediation: remediations[CodeYaegiLoad],
+		Version:     "v1.0.0",
+		DocsURL:     docsURL,
+		Log:         "failed to run the plugin with Yaegi",
+		LogFence:    "```",
+	}
+
+	err = tmpl.Execute(io.Discard, sample)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute issue template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+func mustParseDefaultIssueTemplate() *template.Template {
+	return template.Must(template.New("issue").Parse(defaultIssueTemplate))
+}
+
+// issueBody renders the body of the analyzer issue.
+// The log is truncated, keeping its start and its end, to fit in the GitHub body size limit.
+func (s *Scrapper) issueBody(ctx context.Context, err error) (string, error) {
+	aErr := AsError(err)
+
+	data := issueData{
+		Code:        aErr.Code,
+		Category:    aErr.Category,
+		Remediation: remediations[aErr.Code],
+		Version:     aErr.Version,
+		DocsURL:     docsURL,
+		Log:         safeLog(err),
+	}
+	data.LogFence = codeFence(data.Log)
+
+	body, err := s.renderIssue(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	if len(body) <= maxIssueBodySize {
+		return body, nil
+	}
+
+	data.Log = truncateMiddle(data.Log, len(data.Log)-(len(body)-maxIssueBodySize))
+	data.LogFence = codeFence(data.Log)
+
+	body, err = s.renderIssue(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	// The template itself is too large.
+	return truncateMiddle(body, maxIssueBodySize), nil
+}
+
+// renderIssue renders the issue body.
+// A custom template failing with the data of the issue falls back to the built-in template: the issue is still opened.
+func (s *Scrapper) renderIssue(ctx context.Context, data issueData) (string, error) {
+	var b strings.Builder
+	err := s.issueTemplate.Execute(&b, data)
+	if err == nil {
+		return b.String(), nil
+	}
+
+	log.Ctx(ctx).Error().Err(err).Msg("Failed to render issue with the custom template, falling back to the built-in template")
+
+	b.Reset()
+	if fErr := mustParseDefaultIssueTemplate().Execute(&b, data); fErr != nil {
+		return "", fmt.Errorf("failed to render issue: %w", errors.Join(err, fErr))
+	}
+
+	return b.String(), nil
+}
+
+// codeFence returns a Markdown code fence which can't be closed by the text:
+// at least 3 backticks, and more than the longest backtick run of the text.
+func codeFence(text string) string {
+	var longest, current int
+	for _, r := range text {
+		if r != '`' {
+			current = 0
+			continue
+		}
+
+		current++
+		longest = max(longest, current)
+	}
+
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// truncateMiddle truncates the text to maxSize bytes, keeping its start and its end around a marker.
+// The cuts are aligned on the lines when possible, and always on the runes.
+func truncateMiddle(text string, maxSize int) string {
+	if len(text) <= maxSize {
+		return text
+	}
+
+	// The length of the marker depends on the number of truncated characters, the largest one is used.
+	budget := maxSize - len(fmt.Sprintf(truncatedMarker, len(text)))
+	if budget <= 0 {
+		return ""
+	}
+
+	head := cutBefore(text, budget/2)
+	tail := cutAfter(text, len(text)-(budget-len(head)))
+
+	return head + fmt.Sprintf(truncatedMarker, len(text)-len(head)-len(tail)) + tail
+}
+
+// cutBefore returns the start of the text, of at most size bytes.
+func cutBefore(text string, size int) string {
+	head := text[:size]
+
+	if i := strings.LastIndexByte(head, '\n'); i >= size/2 {
+		return head[:i]
+	}
+
+	for len(head) > 0 && !utf8.RuneStart(text[len(head)]) {
+		head = head[:len(head)-1]
+	}
+
+	return head
+}
+
+// cutAfter returns the end of the text, starting at most at offset.
+func cutAfter(text string, offset int) string {
+	tail := text[offset:]
+
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)/2 {
+		return tail[i+1:]
+	}
+
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+
+	return tail
+}
+
+// withVersion sets the analyzed version on the analysis error.
+func withVersion(err error, version string) error {
+	if err == nil {
+		return nil
+	}
+
+	var aErr *Error
+	if !errors.As(err, &aErr) {
+		aErr = AsError(err)
+		err = aErr
+	}
+
+	aErr.Version = version
+
+	return err
+}
@@ -0,0 +1,197 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrapper_issueBody(t *testing.T) {
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil)
+
+	err := fmt.Errorf("failed to get the latest tag: %w", newError(CodeVersionInvalidTag, errors.New("invalid tag: 1.0")))
+
+	body, err := scrapper.issueBody(context.Background(), withVersion(err, "v1.2.0"))
+	require.NoError(t, err)
+
+	assert.Contains(t, body, "Analyzed version: `v1.2.0`")
+	assert.Contains(t, body, "Error code: `version_invalid_tag` (versioning)")
+	assert.Contains(t, body, "## How to fix\n\n"+remediations[CodeVersionInvalidTag])
+	assert.Contains(t, body, "```\nfailed to get the latest tag: invalid tag: 1.0\n```")
+	assert.Contains(t, body, docsURL)
+}
+
+func TestScrapper_issueBody_unknown(t *testing.T) {
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil)
+
+	body, err := scrapper.issueBody(context.Background(), errors.New("boom"))
+	require.NoError(t, err)
+
+	assert.Contains(t, body, "Error code: `unknown` (unknown)")
+	assert.NotContains(t, body, "Analyzed version")
+	assert.NotContains(t, body, "How to fix")
+}
+
+func TestScrapper_issueBody_truncated(t *testing.T) {
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil)
+
+	log := "start\n" + strings.Repeat("é", maxIssueBodySize) + "\nend"
+
+	body, err := scrapper.issueBody(context.Background(), newError(CodeYaegiLoad, errors.New(log)))
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, len(body), maxIssueBodySize)
+	assert.True(t, utf8.ValidString(body))
+	assert.Contains(t, body, "```\nstart\n")
+	assert.Contains(t, body, "characters truncated ...]")
+	assert.Contains(t, body, "\nend\n```")
+	assert.Contains(t, body, "Traefik Plugin Analyzer will restart when you will close this issue.")
+}
+
+func TestScrapper_issueBody_template(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "issue.md.tmpl")
+	err := os.WriteFile(filename, []byte("{{ .Code }}@{{ .Version }}: {{ .Log }}"), 0o600)
+	require.NoError(t, err)
+
+	tmpl, err := ParseIssueTemplate(filename)
+	require.NoError(t, err)
+
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil, WithIssueTemplate(tmpl))
+
+	body, err := scrapper.issueBody(context.Background(), withVersion(newError(CodeWASMRun, errors.New("boom")), "v0.1.0"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "wasm_run@v0.1.0: boom", body)
+}
+
+func TestParseIssueTemplate_invalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "issue.md.tmpl")
+	err := os.WriteFile(filename, []byte("{{ .Code "), 0o600)
+	require.NoError(t, err)
+
+	_, err = ParseIssueTemplate(filename)
+	assert.Error(t, err)
+}
+
+func TestParseIssueTemplate_execution(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "issue.md.tmpl")
+	err := os.WriteFile(filename, []byte("{{ .Unknown }}"), 0o600)
+	require.NoError(t, err)
+
+	_, err = ParseIssueTemplate(filename)
+	assert.Error(t, err)
+}
+
+func TestScrapper_issueBody_templateFallback(t *testing.T) {
+	// The template fails only with some data: the version is empty when unknown.
+	tmpl, err := template.New("issue").Parse(`{{ slice .Version 1 }}`)
+	require.NoError(t, err)
+
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil, WithIssueTemplate(tmpl))
+
+	body, err := scrapper.issueBody(context.Background(), errors.New("boom"))
+	require.NoError(t, err)
+
+	assert.Contains(t, body, "Error code: `unknown` (unknown)")
+	assert.Contains(t, body, "```\nboom\n```")
+}
+
+func TestScrapper_issueBody_backticks(t *testing.T) {
+	scrapper := NewScrapper(nil, nil, nil, true, nil, nil, nil)
+
+	body, err := scrapper.issueBody(context.Background(), newError(CodeYaegiLoad, errors.New("invalid:\n```\n## Injected")))
+	require.NoError(t, err)
+
+	assert.Contains(t, body, "````\ninvalid:\n```\n## Injected\n````")
+}
+
+func Test_codeFence(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		expected string
+	}{
+		{
+			desc:     "no backticks",
+			text:     "boom",
+			expected: "```",
+		},
+		{
+			desc:     "inline code",
+			text:     "invalid `tag`",
+			expected: "```",
+		},
+		{
+			desc:     "fence",
+			text:     "a\n```\nb",
+			expected: "````",
+		},
+		{
+			desc:     "longest run",
+			text:     "`` a ````` b ```",
+			expected: "``````",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, codeFence(test.text))
+		})
+	}
+}
+
+func Test_truncateMiddle(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		maxSize  int
+		expected string
+	}{
+		{
+			desc:     "short",
+			text:     "abcdef",
+			maxSize:  10,
+			expected: "abcdef",
+		},
+		{
+			desc:     "lines",
+			text:     "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9",
+			maxSize:  60,
+			expected: "line 1\n[... 43 characters truncated ...]\nline 8\nline 9",
+		},
+		{
+			desc:     "single line",
+			text:     strings.Repeat("a", 40) + strings.Repeat("b", 40),
+			maxSize:  60,
+			expected: strings.Repeat("a", 12) + "\n[... 55 characters truncated ...]\n" + strings.Repeat("b", 13),
+		},
+		{
+			desc:     "too small",
+			text:     strings.Repeat("a", 80),
+			maxSize:  10,
+			expected: "",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			truncated := truncateMiddle(test.text, test.maxSize)
+
+			assert.Equal(t, test.expected, truncated)
+			assert.LessOrEqual(t, len(truncated), test.maxSize)
+		})
+	}
+}
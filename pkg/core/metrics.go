@@ -0,0 +1,110 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// metrics the domain metrics of the scrapper.
+type metrics struct {
+	discovered    metric.Int64Counter
+	skipped       metric.Int64Counter
+	plugins       metric.Int64Counter
+	failures      metric.Int64Counter
+	issues        metric.Int64Counter
+	yaegiDuration metric.Float64Histogram
+	wasmDuration  metric.Float64Histogram
+}
+
+// newMetrics creates the instruments of the scrapper.
+// An instrument that cannot be created is replaced by a no-op instrument.
+func newMetrics(meter metric.Meter) *metrics {
+	var m metrics
+	var errs []error
+
+	var err error
+
+	m.discovered, err = meter.Int64Counter("piceus.repositories.discovered",
+		metric.WithDescription("Number of repositories found by the search queries."),
+		metric.WithUnit("{repository}"))
+	errs = append(errs, err)
+
+	m.skipped, err = meter.Int64Counter("piceus.repositories.skipped",
+		metric.WithDescription("Number of repositories not analyzed, by reason."),
+		metric.WithUnit("{repository}"))
+	errs = append(errs, err)
+
+	m.plugins, err = meter.Int64Counter("piceus.plugins.stored",
+		metric.WithDescription("Number of analyzed plugins, by outcome (created, updated, unchanged)."),
+		metric.WithUnit("{plugin}"))
+	errs = append(errs, err)
+
+	m.failures, err = meter.Int64Counter("piceus.failures",
+		metric.WithDescription("Number of failures, by category and code."),
+		metric.WithUnit("{failure}"))
+	errs = append(errs, err)
+
+	m.issues, err = meter.Int64Counter("piceus.issues.opened",
+		metric.WithDescription("Number of analyzer issues opened."),
+		metric.WithUnit("{issue}"))
+	errs = append(errs, err)
+
+	m.yaegiDuration, err = meter.Float64Histogram("piceus.yaegi.check.duration",
+		metric.WithDescription("Duration of the Yaegi plugin checks."),
+		metric.WithUnit("s"))
+	errs = append(errs, err)
+
+	m.wasmDuration, err = meter.Float64Histogram("piceus.wasm.check.duration",
+		metric.WithDescription("Duration of the WASM plugin checks."),
+		metric.WithUnit("s"))
+	errs = append(errs, err)
+
+	if err = errors.Join(errs...); err != nil {
+		log.Warn().Err(err).Msg("Failed to create the scrapper metrics")
+	}
+
+	return &m
+}
+
+func (m *metrics) repositoriesDiscovered(ctx context.Context, count int) {
+	m.discovered.Add(ctx, int64(count))
+}
+
+func (m *metrics) repositorySkipped(ctx context.Context, reason string) {
+	m.skipped.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+func (m *metrics) pluginStored(ctx context.Context, outcome string) {
+	m.plugins.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+func (m *metrics) failure(ctx context.Context, err *Error) {
+	m.failures.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("category", string(err.Category)),
+		attribute.String("code", string(err.Code)),
+	))
+}
+
+func (m *metrics) issueOpened(ctx context.Context) {
+	m.issues.Add(ctx, 1)
+}
+
+// checkDuration records the duration of a plugin check by runtime.
+func (m *metrics) checkDuration(ctx context.Context, runtime string, start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	histogram := m.yaegiDuration
+	if runtime == wasmRuntime {
+		histogram = m.wasmDuration
+	}
+
+	histogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attribute.String("result", result)))
+}
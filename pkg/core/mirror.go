@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+)
+
+var assetExtensions = map[string]string{
+	contentTypePNG:  ".png",
+	contentTypeJPEG: ".jpg",
+	contentTypeSVG:  ".svg",
+}
+
+// assetMirror stores the plugin images under a content-hash name, in a directory served at a base URL.
+type assetMirror struct {
+	dir     string
+	baseURL *url.URL
+}
+
+// store writes the asset, if not already stored, and returns its mirrored URL.
+func (m *assetMirror) store(a *asset) (string, error) {
+	ext, ok := assetExtensions[a.ContentType]
+	if !ok {
+		return "", fmt.Errorf("unsupported content type: %s", a.ContentType)
+	}
+
+	sum := sha256.Sum256(a.Content)
+	name := hex.EncodeToString(sum[:]) + ext
+
+	filename := filepath.Join(m.dir, name)
+
+	// The name depends on the content: an existing file is the same image.
+	_, err := os.Stat(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		err = writeAsset(filename, a.Content)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to store %s: %w", name, err)
+	}
+
+	return m.baseURL.JoinPath(name).String(), nil
+}
+
+// assetURL returns the URL of an asset: the mirrored URL if the mirroring is enabled, the repository URL otherwise.
+// The repository URL is used if the asset cannot be mirrored,
+// except for a sanitized SVG: the repository serves the original content, the asset is ignored with a warning.
+func (s *Scrapper) assetURL(ctx context.Context, a *asset, spec assetSpec) (string, []Warning) {
+	if a == nil {
+		return "", nil
+	}
+
+	if s.mirror == nil || s.dryRun || len(a.Content) == 0 {
+		return unmirroredURL(a, spec)
+	}
+
+	mirrored, err := s.mirror.store(a)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("url", a.URL).Msg("Failed to mirror the image")
+		return unmirroredURL(a, spec)
+	}
+
+	return mirrored, nil
+}
+
+// unmirroredURL returns the repository URL of an asset, or an empty string if the SVG has been sanitized.
+func unmirroredURL(a *asset, spec assetSpec) (string, []Warning) {
+	if a.Sanitized {
+		return "", []Warning{newWarning(WarningAssetSanitized, "the %s %q has active content and cannot be served from the assets mirror, it is ignored", spec.name, a.URL)}
+	}
+
+	return a.URL, nil
+}
+
+// writeAsset writes a file atomically.
+func writeAsset(filename string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(filename), 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".asset-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// The assets are served by a static file server.
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
@@ -0,0 +1,135 @@
+package core
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// svgHash the SHA-256 of "<svg></svg>".
+const svgHash = "b12e0d83ce2357d80b89c57694814d0a3abdaf8c40724f2049af8b7f01b7812b"
+
+func Test_assetMirror_store(t *testing.T) {
+	dir := t.TempDir()
+
+	baseURL, err := url.Parse("https://assets.example.com/plugins")
+	require.NoError(t, err)
+
+	mirror := &assetMirror{dir: dir, baseURL: baseURL}
+
+	mirrored, err := mirror.store(&asset{ContentType: contentTypeSVG, Content: []byte("<svg></svg>")})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://assets.example.com/plugins/"+svgHash+".svg", mirrored)
+
+	content, err := os.ReadFile(filepath.Join(dir, svgHash+".svg"))
+	require.NoError(t, err)
+	assert.Equal(t, "<svg></svg>", string(content))
+
+	// Same content, same file.
+	again, err := mirror.store(&asset{ContentType: contentTypeSVG, Content: []byte("<svg></svg>")})
+	require.NoError(t, err)
+	assert.Equal(t, mirrored, again)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestScrapper_assetURL(t *testing.T) {
+	baseURL, err := url.Parse("https://assets.example.com")
+	require.NoError(t, err)
+
+	rawURL := "https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/icon.svg"
+
+	sanitized := &asset{URL: rawURL, ContentType: contentTypeSVG, Content: []byte("<svg></svg>"), Sanitized: true}
+
+	testCases := []struct {
+		desc             string
+		asset            *asset
+		dryRun           bool
+		mirror           bool
+		expected         string
+		expectedWarnings int
+	}{
+		{
+			desc:     "no asset",
+			mirror:   true,
+			expected: "",
+		},
+		{
+			desc:     "mirroring disabled",
+			asset:    &asset{URL: rawURL, ContentType: contentTypeSVG, Content: []byte("<svg></svg>")},
+			expected: rawURL,
+		},
+		{
+			desc:     "mirrored",
+			asset:    &asset{URL: rawURL, ContentType: contentTypeSVG, Content: []byte("<svg></svg>")},
+			mirror:   true,
+			expected: "https://assets.example.com/" + svgHash + ".svg",
+		},
+		{
+			desc:     "dry run",
+			asset:    &asset{URL: rawURL, ContentType: contentTypeSVG, Content: []byte("<svg></svg>")},
+			mirror:   true,
+			dryRun:   true,
+			expected: rawURL,
+		},
+		{
+			desc:     "not fetched",
+			asset:    &asset{URL: rawURL},
+			mirror:   true,
+			expected: rawURL,
+		},
+		{
+			desc:     "sanitized and mirrored",
+			asset:    sanitized,
+			mirror:   true,
+			expected: "https://assets.example.com/" + svgHash + ".svg",
+		},
+		{
+			desc:             "sanitized without mirror",
+			asset:            sanitized,
+			expected:         "",
+			expectedWarnings: 1,
+		},
+		{
+			desc:             "sanitized in dry run",
+			asset:            sanitized,
+			mirror:           true,
+			dryRun:           true,
+			expected:         "",
+			expectedWarnings: 1,
+		},
+		{
+			desc:             "sanitized exceeding the limits",
+			asset:            &asset{URL: rawURL, Sanitized: true},
+			mirror:           true,
+			expected:         "",
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var opts []Option
+			if test.mirror {
+				opts = append(opts, WithAssetMirror(t.TempDir(), baseURL))
+			}
+
+			scrapper := NewScrapper(nil, nil, nil, test.dryRun, nil, nil, nil, opts...)
+
+			assetURL, warnings := scrapper.assetURL(context.Background(), test.asset, iconSpec)
+
+			assert.Equal(t, test.expected, assetURL)
+			assert.Len(t, warnings, test.expectedWarnings)
+		})
+	}
+}
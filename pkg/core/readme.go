@@ -0,0 +1,428 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	stdhtml "html"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/traefik/piceus/internal/plugin"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// maxReadmeSize the maximum size of a README, the larger READMEs are truncated.
+const maxReadmeSize = 512 * 1024
+
+const truncatedReadmeNote = "\n\n---\n\nThe README is truncated, the full README is available on [GitHub](%s).\n"
+
+var (
+	// inlineTargetRe matches the target of an inline link or image: [text](target) or ![alt](target).
+	inlineTargetRe = regexp.MustCompile(`\]\([ \t]*<?((?:[^()\s<>]|\([^()\s]*\))+)`)
+	// referenceTargetRe matches the target of a link reference definition: [label]: target.
+	referenceTargetRe = regexp.MustCompile(`(?m)^[ ]{0,3}\[[^\]]+\]:[ \t]*<?([^\s>]+)`)
+	// htmlTargetRe matches the src and href attributes of the inline HTML.
+	htmlTargetRe = regexp.MustCompile(`(?i)\s(src|href)\s*=\s*["']([^"']+)["']`)
+	// autoLinkRe matches an autolink: <scheme:target>.
+	autoLinkRe = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*)>`)
+)
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	// The HTML is sanitized after the rendering.
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// allowedSchemes the URL schemes allowed in the link and image targets, the other targets must be relative.
+var allowedSchemes = []string{"http", "https", "mailto"}
+
+var htmlPolicy = newHTMLPolicy()
+
+// newHTMLPolicy creates the policy of the HTML allowed in the READMEs:
+// the user generated content policy without scripts, iframes, styles, forms, and event handlers,
+// with the attributes used by the READMEs layouts.
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+
+	policy.AllowAttrs("align").OnElements("p", "div", "img", "h1", "h2", "h3", "h4", "h5", "h6", "table", "td", "th")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+
+	return policy
+}
+
+type replacement struct {
+	start, end int
+	value      string
+}
+
+// readmeLinks rewrites the relative targets of the README links and images.
+type readmeLinks struct {
+	// blobURL the base URL of the repository files pinned to a version (links).
+	blobURL *url.URL
+	// rawURL the base URL of the raw repository files pinned to a version (images).
+	rawURL *url.URL
+	// dir the directory of the README in the repository.
+	dir string
+}
+
+// rewrite rewrites the relative targets of the links and images to absolute URLs.
+// The anchors, the absolute URLs, and the code are kept unchanged.
+func (r readmeLinks) rewrite(content string) string {
+	doc := inspectMarkdown([]byte(content))
+
+	replacements := doc.replaceTargets(content, r.resolve)
+
+	for _, m := range htmlTargetRe.FindAllStringSubmatchIndex(content, -1) {
+		target := content[m[4]:m[5]]
+
+		replacements = append(replacements, replacement{start: m[4], end: m[5], value: r.resolve(target, strings.EqualFold(content[m[2]:m[3]], "src"))})
+	}
+
+	return applyReplacements(content, replacements, doc.code)
+}
+
+// resolve returns the absolute URL of a relative target, or the target itself.
+func (r readmeLinks) resolve(target string, image bool) string {
+	if strings.HasPrefix(target, "#") || strings.HasPrefix(target, "//") {
+		return target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return target
+	}
+
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join(r.dir, p)
+	}
+
+	// The target cannot go out of the repository.
+	p = path.Clean("/" + p)
+
+	baseURL := r.blobURL
+	if image {
+		baseURL = r.rawURL
+	}
+
+	abs := baseURL.JoinPath(p)
+	abs.RawQuery = u.RawQuery
+	abs.Fragment = u.Fragment
+
+	return abs.String()
+}
+
+// markdownDoc the elements of a Markdown document.
+type markdownDoc struct {
+	// links the destinations of the links.
+	links map[string]bool
+	// images the destinations of the images.
+	images map[string]bool
+	// autoLinks the URLs of the autolinks (<scheme:target>).
+	autoLinks map[string]bool
+	// code the byte ranges of the code blocks and spans.
+	code [][2]int
+	// html the byte ranges of the HTML blocks and of the inline HTML.
+	html [][2]int
+}
+
+// inspectMarkdown parses a Markdown document.
+func inspectMarkdown(source []byte) markdownDoc {
+	doc := markdownDoc{
+		links:     map[string]bool{},
+		images:    map[string]bool{},
+		autoLinks: map[string]bool{},
+	}
+
+	root := markdown.Parser().Parse(text.NewReader(source))
+
+	_ = ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Link:
+			doc.links[string(n.Destination)] = true
+
+		case *ast.Image:
+			doc.images[string(n.Destination)] = true
+
+		case *ast.AutoLink:
+			if n.AutoLinkType == ast.AutoLinkURL {
+				doc.autoLinks[string(n.URL(source))] = true
+			}
+
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			lines := n.Lines()
+			for i := range lines.Len() {
+				segment := lines.At(i)
+				doc.code = append(doc.code, [2]int{segment.Start, segment.Stop})
+			}
+
+			return ast.WalkSkipChildren, nil
+
+		case *ast.CodeSpan:
+			for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					doc.code = append(doc.code, [2]int{t.Segment.Start, t.Segment.Stop})
+				}
+			}
+
+			return ast.WalkSkipChildren, nil
+
+		case *ast.HTMLBlock:
+			lines := n.Lines()
+			if lines.Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+
+			stop := lines.At(lines.Len() - 1).Stop
+			if n.HasClosure() {
+				stop = n.ClosureLine.Stop
+			}
+
+			doc.html = append(doc.html, [2]int{lines.At(0).Start, stop})
+
+			return ast.WalkSkipChildren, nil
+
+		case *ast.RawHTML:
+			if n.Segments.Len() > 0 {
+				doc.html = append(doc.html, [2]int{n.Segments.At(0).Start, n.Segments.At(n.Segments.Len() - 1).Stop})
+			}
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return doc
+}
+
+// replaceTargets returns the replacements of the targets of the Markdown links and images (inline and reference definitions).
+func (d markdownDoc) replaceTargets(content string, replace func(target string, image bool) string) []replacement {
+	var replacements []replacement
+
+	for _, m := range inlineTargetRe.FindAllStringSubmatchIndex(content, -1) {
+		target := content[m[2]:m[3]]
+		if !d.links[target] && !d.images[target] {
+			continue
+		}
+
+		replacements = append(replacements, replacement{start: m[2], end: m[3], value: replace(target, isImage(content, m[0]))})
+	}
+
+	for _, m := range referenceTargetRe.FindAllStringSubmatchIndex(content, -1) {
+		target := content[m[2]:m[3]]
+		if !d.links[target] && !d.images[target] {
+			continue
+		}
+
+		replacements = append(replacements, replacement{start: m[2], end: m[3], value: replace(target, !d.links[target])})
+	}
+
+	return replacements
+}
+
+// applyReplacements applies the replacements to the content, except inside the excluded ranges.
+func applyReplacements(content string, replacements []replacement, excluded [][2]int) string {
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	var b strings.Builder
+	b.Grow(len(content))
+
+	var last int
+	for _, rep := range replacements {
+		if rep.start < last || inRanges(excluded, rep.start) {
+			continue
+		}
+
+		b.WriteString(content[last:rep.start])
+		b.WriteString(rep.value)
+		last = rep.end
+	}
+
+	b.WriteString(content[last:])
+
+	return b.String()
+}
+
+// isImage returns true if the "](" at pos closes the alt text of an image.
+func isImage(content string, pos int) bool {
+	depth := 0
+
+	for i := pos; i >= 0; i-- {
+		switch content[i] {
+		case ']':
+			depth++
+		case '[':
+			depth--
+			if depth == 0 {
+				return i > 0 && content[i-1] == '!'
+			}
+		}
+	}
+
+	return false
+}
+
+func inRanges(ranges [][2]int, pos int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sanitizeReadme removes the dangerous HTML (scripts, iframes, event handlers, ...) and the dangerous link targets (ex: javascript:) of a README.
+// Only the relative targets and the http, https and mailto URLs are kept: the other link targets are replaced by "#", the other autolinks are removed.
+// The code is kept unchanged.
+func sanitizeReadme(content string) string {
+	doc := inspectMarkdown([]byte(content))
+
+	replacements := doc.replaceTargets(content, func(target string, _ bool) string {
+		if !isAllowedURL(target) {
+			return "#"
+		}
+
+		return target
+	})
+
+	for _, m := range autoLinkRe.FindAllStringSubmatchIndex(content, -1) {
+		target := content[m[2]:m[3]]
+		if doc.autoLinks[target] && !isAllowedURL(target) {
+			replacements = append(replacements, replacement{start: m[0], end: m[1]})
+		}
+	}
+
+	for _, r := range doc.html {
+		replacements = append(replacements, replacement{start: r[0], end: r[1], value: htmlPolicy.Sanitize(content[r[0]:r[1]])})
+	}
+
+	return applyReplacements(content, replacements, doc.code)
+}
+
+// isAllowedURL returns true if the target is relative, or if its scheme is allowed.
+// The target is decoded before the check, like it is read by a browser:
+// the backslash escapes and the character references are resolved, the tabs and the newlines are ignored.
+func isAllowedURL(target string) bool {
+	decoded := stdhtml.UnescapeString(string(util.UnescapePunctuations([]byte(target))))
+	decoded = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, decoded)
+	decoded = strings.TrimLeftFunc(decoded, func(r rune) bool { return r <= ' ' })
+
+	scheme, _, found := strings.Cut(decoded, ":")
+	if !found || strings.ContainsAny(scheme, "/?#") {
+		return true
+	}
+
+	return slices.Contains(allowedSchemes, strings.ToLower(scheme))
+}
+
+// truncateReadme truncates the README to the maximum size, on a line boundary.
+// A note linking to the full README is appended.
+func truncateReadme(content string, maxSize int, fullURL string) string {
+	if len(content) <= maxSize {
+		return content
+	}
+
+	note := fmt.Sprintf(truncatedReadmeNote, fullURL)
+	closeFence := "\n```"
+
+	cut := maxSize - len(note) - len(closeFence)
+	if cut <= 0 {
+		return ""
+	}
+
+	if i := strings.LastIndexByte(content[:cut], '\n'); i > 0 {
+		cut = i
+	}
+
+	doc := inspectMarkdown([]byte(content))
+	if inRanges(doc.code, cut) {
+		return content[:cut] + closeFence + note
+	}
+
+	return content[:cut] + note
+}
+
+// renderReadme renders a README to sanitized HTML, and returns its table of contents.
+func renderReadme(content string) (string, []plugin.Heading, error) {
+	source := []byte(content)
+
+	root := markdown.Parser().Parse(text.NewReader(source))
+
+	var toc []plugin.Heading
+
+	_ = ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := node.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+
+		var id string
+		if value, found := heading.AttributeString("id"); found {
+			if b, ok := value.([]byte); ok {
+				id = string(b)
+			}
+		}
+
+		toc = append(toc, plugin.Heading{Level: heading.Level, Title: nodeText(heading, source), ID: id})
+
+		return ast.WalkSkipChildren, nil
+	})
+
+	var buf bytes.Buffer
+	if err := markdown.Renderer().Render(&buf, source, root); err != nil {
+		return "", nil, fmt.Errorf("failed to render readme: %w", err)
+	}
+
+	return htmlPolicy.Sanitize(buf.String()), toc, nil
+}
+
+// nodeText returns the text of the node and its descendants.
+func nodeText(node ast.Node, source []byte) string {
+	var b strings.Builder
+
+	_ = ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch t := n.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+
+		case *ast.String:
+			b.Write(t.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(b.String())
+}
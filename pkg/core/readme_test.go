@@ -0,0 +1,241 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+func Test_readmeLinks_rewrite(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		dir      string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "relative link",
+			content:  "See [the configuration](./docs/config.md).",
+			expected: "See [the configuration](https://github.com/traefik/plugintest/blob/v1.0.0/docs/config.md).",
+		},
+		{
+			desc:     "relative link with anchor",
+			content:  "See [the configuration](docs/config.md#headers).",
+			expected: "See [the configuration](https://github.com/traefik/plugintest/blob/v1.0.0/docs/config.md#headers).",
+		},
+		{
+			desc:     "relative image",
+			content:  "![](img/diagram.png)",
+			expected: "![](https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/img/diagram.png)",
+		},
+		{
+			desc:     "image with title",
+			content:  `![Diagram](/img/diagram.png "The diagram")`,
+			expected: `![Diagram](https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/img/diagram.png "The diagram")`,
+		},
+		{
+			desc:     "image inside a link",
+			content:  "[![Logo](logo.png)](LICENSE)",
+			expected: "[![Logo](https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/logo.png)](https://github.com/traefik/plugintest/blob/v1.0.0/LICENSE)",
+		},
+		{
+			desc:     "README in a sub directory",
+			dir:      ".github",
+			content:  "[Contributing](CONTRIBUTING.md) [License](../LICENSE)",
+			expected: "[Contributing](https://github.com/traefik/plugintest/blob/v1.0.0/.github/CONTRIBUTING.md) [License](https://github.com/traefik/plugintest/blob/v1.0.0/LICENSE)",
+		},
+		{
+			desc:     "out of the repository",
+			content:  "[License](../../LICENSE)",
+			expected: "[License](https://github.com/traefik/plugintest/blob/v1.0.0/LICENSE)",
+		},
+		{
+			desc:     "reference definitions",
+			content:  "[Docs][docs] ![Logo][logo]\n\n[docs]: ./docs/README.md\n[logo]: <logo.png>\n",
+			expected: "[Docs][docs] ![Logo][logo]\n\n[docs]: https://github.com/traefik/plugintest/blob/v1.0.0/docs/README.md\n[logo]: <https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/logo.png>\n",
+		},
+		{
+			desc:     "inline HTML",
+			content:  `<a href="docs/config.md"><img src="./logo.png" width="100"></a>`,
+			expected: `<a href="https://github.com/traefik/plugintest/blob/v1.0.0/docs/config.md"><img src="https://raw.githubusercontent.com/traefik/plugintest/v1.0.0/logo.png" width="100"></a>`,
+		},
+		{
+			desc:     "anchors and absolute URLs",
+			content:  "[Usage](#usage) [Traefik](https://traefik.io) [Mail](mailto:contact@example.com) ![](//example.com/logo.png)",
+			expected: "[Usage](#usage) [Traefik](https://traefik.io) [Mail](mailto:contact@example.com) ![](//example.com/logo.png)",
+		},
+		{
+			desc:     "code",
+			content:  "[Docs](docs.md)\n\n```markdown\n[Docs](docs.md)\n```\n\n`[Docs](docs.md)`\n",
+			expected: "[Docs](https://github.com/traefik/plugintest/blob/v1.0.0/docs.md)\n\n```markdown\n[Docs](docs.md)\n```\n\n`[Docs](docs.md)`\n",
+		},
+		{
+			desc:     "not a link",
+			content:  `The value of a\[1\](2).`,
+			expected: `The value of a\[1\](2).`,
+		},
+	}
+
+	blobURL, err := url.Parse("https://github.com/traefik/plugintest/blob/v1.0.0")
+	require.NoError(t, err)
+
+	rawURL, err := url.Parse("https://raw.githubusercontent.com/traefik/plugintest/v1.0.0")
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			dir := test.dir
+			if dir == "" {
+				dir = "."
+			}
+
+			links := readmeLinks{blobURL: blobURL, rawURL: rawURL, dir: dir}
+
+			assert.Equal(t, test.expected, links.rewrite(test.content))
+		})
+	}
+}
+
+func Test_sanitizeReadme(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected string
+	}{
+		{
+			desc:     "script block",
+			content:  "# Title\n\n<script>alert(1)</script>\n\nText\n",
+			expected: "# Title\n\n\n\nText\n",
+		},
+		{
+			desc:     "iframe",
+			content:  "Video:\n\n<iframe src=\"https://example.com\"></iframe>\n",
+			expected: "Video:\n\n\n",
+		},
+		{
+			desc:     "event handler",
+			content:  "<p align=\"center\"><img src=\"logo.png\" onerror=\"alert(1)\" width=\"100\"></p>\n",
+			expected: "<p align=\"center\"><img src=\"logo.png\" width=\"100\"></p>\n",
+		},
+		{
+			desc:     "inline HTML",
+			content:  "Text <b onclick=\"alert(1)\">bold</b> text.\n",
+			expected: "Text <b>bold</b> text.\n",
+		},
+		{
+			desc:     "javascript link",
+			content:  "[Click](javascript:alert(1)) [Docs](docs.md)\n",
+			expected: "[Click](#) [Docs](docs.md)\n",
+		},
+		{
+			desc:     "character reference",
+			content:  "[x](javascript&#58;alert(1)) [y](java&#x73;cript:alert(1)) [z](javascript&colon;alert(1))\n",
+			expected: "[x](#) [y](#) [z](#)\n",
+		},
+		{
+			desc:     "backslash escape",
+			content:  "[x](javascript\\:alert(1))\n",
+			expected: "[x](#)\n",
+		},
+		{
+			desc:     "other schemes",
+			content:  "[x](VBScript:msgbox) ![y](data:image/svg+xml;base64,PHN2Zz4=) [z](<javascript:alert(1)>) [w](ftp://example.com)\n",
+			expected: "[x](#) ![y](#) [z](<#>) [w](#)\n",
+		},
+		{
+			desc:     "reference definition",
+			content:  "[x]\n\n[x]: javascript&#58;alert(1)\n",
+			expected: "[x]\n\n[x]: #\n",
+		},
+		{
+			desc:     "autolink",
+			content:  "Click <javascript:alert(1)> or <https://example.com> or <contact@example.com>.\n",
+			expected: "Click  or <https://example.com> or <contact@example.com>.\n",
+		},
+		{
+			desc:     "allowed targets",
+			content:  "[a](https://example.com) [b](mailto:contact@example.com) [c](#usage) [d](docs/a.md?x=1:2) [e](//example.com)\n",
+			expected: "[a](https://example.com) [b](mailto:contact@example.com) [c](#usage) [d](docs/a.md?x=1:2) [e](//example.com)\n",
+		},
+		{
+			desc:     "code",
+			content:  "```html\n<script>alert(1)</script>\n```\n\n`<script>`\n",
+			expected: "```html\n<script>alert(1)</script>\n```\n\n`<script>`\n",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, sanitizeReadme(test.content))
+		})
+	}
+}
+
+func Test_truncateReadme(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		maxSize  int
+		expected string
+	}{
+		{
+			desc:     "small",
+			content:  "# Title\n\nText\n",
+			maxSize:  200,
+			expected: "# Title\n\nText\n",
+		},
+		{
+			desc:     "text",
+			content:  "# Title\n\n" + strings.Repeat("Line\n", 40),
+			maxSize:  200,
+			expected: "# Title\n\n" + strings.Repeat("Line\n", 15) + "Line" + fmt.Sprintf(truncatedReadmeNote, "https://example.com/README.md"),
+		},
+		{
+			desc:     "code block",
+			content:  "# Title\n\n```yaml\n" + strings.Repeat("key: value\n", 40) + "```\n",
+			maxSize:  200,
+			expected: "# Title\n\n```yaml\n" + strings.Repeat("key: value\n", 6) + "```" + fmt.Sprintf(truncatedReadmeNote, "https://example.com/README.md"),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			truncated := truncateReadme(test.content, test.maxSize, "https://example.com/README.md")
+
+			assert.Equal(t, test.expected, truncated)
+			assert.LessOrEqual(t, len(truncated), test.maxSize)
+		})
+	}
+}
+
+func Test_renderReadme(t *testing.T) {
+	content := "# My Plugin\n\n<script>alert(1)</script>\n\n## Configuration `yaml`\n\n```go\nfunc main() {}\n```\n\n## Usage\n\n[Docs](https://example.com) <img src=\"logo.png\" onerror=\"alert(1)\">\n"
+
+	readmeHTML, toc, err := renderReadme(content)
+	require.NoError(t, err)
+
+	expectedTOC := []plugin.Heading{
+		{Level: 1, Title: "My Plugin", ID: "my-plugin"},
+		{Level: 2, Title: "Configuration yaml", ID: "configuration-yaml"},
+		{Level: 2, Title: "Usage", ID: "usage"},
+	}
+	assert.Equal(t, expectedTOC, toc)
+
+	assert.Contains(t, readmeHTML, `<h1 id="my-plugin">My Plugin</h1>`)
+	assert.Contains(t, readmeHTML, `<code class="language-go">`)
+	assert.Contains(t, readmeHTML, `<a href="https://example.com" rel="nofollow">Docs</a>`)
+	assert.Contains(t, readmeHTML, `<img src="logo.png">`)
+	assert.NotContains(t, readmeHTML, "script")
+	assert.NotContains(t, readmeHTML, "onerror")
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+// Reconciliation modes of the plugins that disappeared from the search results.
+const (
+	ReconcileNone      = "none"
+	ReconcileDeprecate = "deprecate"
+	ReconcileDelete    = "delete"
+)
+
+// reconcile deprecates or deletes the catalog entries that are not in the search results anymore
+// (repository deleted, archived, made private, renamed, or without the plugin topic).
+func (s *Scrapper) reconcile(ctx context.Context, repositories []*github.Repository) error {
+	if s.reconcileMode == "" || s.reconcileMode == ReconcileNone {
+		return nil
+	}
+
+	ctx, span := s.tracer.Start(ctx, "scrapper_reconcile")
+	defer span.End()
+
+	logger := log.Ctx(ctx).With().Str("reconcile_mode", s.reconcileMode).Logger()
+
+	plugins, err := s.pg.List(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to list plugins: %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(repositories))
+	for _, repository := range repositories {
+		seen[strings.ToLower(repository.GetFullName())] = struct{}{}
+	}
+
+	var missing []plugin.Plugin
+	for _, p := range plugins {
+		if _, ok := seen[strings.ToLower(p.Author+"/"+p.RepoName)]; ok {
+			continue
+		}
+
+		if s.reconcileMode == ReconcileDeprecate && p.Deprecated {
+			continue
+		}
+
+		missing = append(missing, p)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Safety threshold against mass deletion (ex: GitHub search outage, wrong search queries).
+	if float64(len(missing)) > s.reconcileMaxRatio*float64(len(plugins)) {
+		err = fmt.Errorf("too many plugins to reconcile: %d of %d (max ratio %.2f)", len(missing), len(plugins), s.reconcileMaxRatio)
+		span.RecordError(err)
+		return err
+	}
+
+	for _, p := range missing {
+		pLogger := logger.With().Str("module_name", p.Name).Logger()
+
+		if s.dryRun {
+			pLogger.Info().Msg("Dry run, not reconciling the plugin")
+			continue
+		}
+
+		err = s.reconcilePlugin(ctx, p)
+		if err != nil {
+			span.RecordError(err)
+			pLogger.Error().Err(err).Msg("Failed to reconcile plugin")
+			continue
+		}
+
+		pLogger.Info().Msg("Reconciled")
+	}
+
+	return nil
+}
+
+// reconcilePlugin deprecates or deletes a catalog entry, according to the reconcile mode.
+func (s *Scrapper) reconcilePlugin(ctx context.Context, p plugin.Plugin) error {
+	switch s.reconcileMode {
+	case ReconcileDeprecate:
+		return s.pg.Deprecate(ctx, p)
+	case ReconcileDelete:
+		return s.pg.Delete(ctx, p.ID)
+	default:
+		return fmt.Errorf("unsupported reconcile mode: %s", s.reconcileMode)
+	}
+}
@@ -0,0 +1,317 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/plugin"
+)
+
+func TestScrapper_reconcile(t *testing.T) {
+	catalog := []plugin.Plugin{
+		{ID: "1", Name: "github.com/traefik/plugin-a", Author: "traefik", RepoName: "plugin-a"},
+		{ID: "2", Name: "github.com/traefik/plugin-b", Author: "traefik", RepoName: "plugin-b"},
+		{ID: "3", Name: "github.com/traefik/plugin-c", Author: "traefik", RepoName: "plugin-c"},
+		{ID: "4", Name: "github.com/traefik/plugin-d", Author: "traefik", RepoName: "plugin-d", Deprecated: true},
+	}
+
+	testCases := []struct {
+		desc               string
+		mode               string
+		maxRatio           float64
+		dryRun             bool
+		repositories       []string
+		expectedDeprecated []string
+		expectedDeleted    []string
+		expectError        bool
+	}{
+		{
+			desc:         "disabled",
+			mode:         ReconcileNone,
+			maxRatio:     1,
+			repositories: []string{"traefik/plugin-a"},
+		},
+		{
+			desc:               "deprecate",
+			mode:               ReconcileDeprecate,
+			maxRatio:           0.5,
+			repositories:       []string{"traefik/plugin-a", "Traefik/Plugin-B", "traefik/plugin-d"},
+			expectedDeprecated: []string{"github.com/traefik/plugin-c"},
+		},
+		{
+			desc:            "delete",
+			mode:            ReconcileDelete,
+			maxRatio:        0.5,
+			repositories:    []string{"traefik/plugin-a", "traefik/plugin-b"},
+			expectedDeleted: []string{"3", "4"},
+		},
+		{
+			desc:         "dry run",
+			mode:         ReconcileDelete,
+			maxRatio:     0.5,
+			dryRun:       true,
+			repositories: []string{"traefik/plugin-a", "traefik/plugin-b"},
+		},
+		{
+			desc:         "safety threshold",
+			mode:         ReconcileDelete,
+			maxRatio:     0.5,
+			repositories: []string{"traefik/plugin-a"},
+			expectError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var deprecated, deleted []string
+			pgClient := &mockPluginClient{
+				list: func() ([]plugin.Plugin, error) {
+					return catalog, nil
+				},
+				deprecate: func(p plugin.Plugin) error {
+					deprecated = append(deprecated, p.Name)
+					return nil
+				},
+				delete: func(id string) error {
+					deleted = append(deleted, id)
+					return nil
+				},
+			}
+
+			var repositories []*github.Repository
+			for _, name := range test.repositories {
+				repositories = append(repositories, &github.Repository{FullName: github.String(name)})
+			}
+
+			scrapper := NewScrapper(nil, nil, pgClient, test.dryRun, nil, nil, nil, WithReconciliation(test.mode, test.maxRatio))
+
+			err := scrapper.reconcile(context.Background(), repositories)
+			if test.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedDeprecated, deprecated)
+			assert.Equal(t, test.expectedDeleted, deleted)
+		})
+	}
+}
+
+func TestScrapper_ReconcileRepository(t *testing.T) {
+	catalog := []plugin.Plugin{
+		{ID: "1", Name: "github.com/traefik/plugin-a", Author: "traefik", RepoName: "plugin-a"},
+		{ID: "2", Name: "github.com/traefik/plugin-b", Author: "traefik", RepoName: "plugin-b", Deprecated: true},
+	}
+
+	testCases := []struct {
+		desc               string
+		mode               string
+		dryRun             bool
+		repository         string
+		expectedOutcome    string
+		expectedDeprecated []string
+		expectedDeleted    []string
+	}{
+		{
+			desc:            "none",
+			mode:            ReconcileNone,
+			repository:      "plugin-a",
+			expectedOutcome: OutcomeUnchanged,
+		},
+		{
+			desc:               "deprecate",
+			mode:               ReconcileDeprecate,
+			repository:         "Plugin-A",
+			expectedOutcome:    OutcomeReconciled,
+			expectedDeprecated: []string{"github.com/traefik/plugin-a"},
+		},
+		{
+			desc:            "already deprecated",
+			mode:            ReconcileDeprecate,
+			repository:      "plugin-b",
+			expectedOutcome: OutcomeUnchanged,
+		},
+		{
+			desc:            "delete",
+			mode:            ReconcileDelete,
+			repository:      "plugin-b",
+			expectedOutcome: OutcomeReconciled,
+			expectedDeleted: []string{"2"},
+		},
+		{
+			desc:            "not in the catalog",
+			mode:            ReconcileDelete,
+			repository:      "plugin-c",
+			expectedOutcome: OutcomeUnchanged,
+		},
+		{
+			desc:            "dry run",
+			mode:            ReconcileDelete,
+			dryRun:          true,
+			repository:      "plugin-a",
+			expectedOutcome: OutcomeUnchanged,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			var deprecated, deleted []string
+			pgClient := &mockPluginClient{
+				list: func() ([]plugin.Plugin, error) {
+					return catalog, nil
+				},
+				deprecate: func(p plugin.Plugin) error {
+					deprecated = append(deprecated, p.Name)
+					return nil
+				},
+				delete: func(id string) error {
+					deleted = append(deleted, id)
+					return nil
+				},
+			}
+
+			scrapper := NewScrapper(nil, nil, pgClient, test.dryRun, nil, nil, nil, WithReconciliation(test.mode, 1))
+
+			result, err := scrapper.ReconcileRepository(context.Background(), "traefik", test.repository)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedOutcome, result.Outcome)
+			assert.Equal(t, test.expectedDeprecated, deprecated)
+			assert.Equal(t, test.expectedDeleted, deleted)
+
+			stored, ok := scrapper.Result("traefik", test.repository)
+			require.True(t, ok)
+			assert.Equal(t, test.expectedOutcome, stored.Outcome)
+		})
+	}
+}
+
+func TestScrapper_search(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		queries          []string
+		expected         []string
+		expectedComplete bool
+	}{
+		{
+			desc:             "pages of several queries",
+			queries:          []string{"paged", "single"},
+			expected:         []string{"traefik/paged-1", "traefik/paged-2", "traefik/single-1"},
+			expectedComplete: true,
+		},
+		{
+			desc:     "incomplete results",
+			queries:  []string{"single", "incomplete"},
+			expected: []string{"traefik/single-1", "traefik/incomplete-1"},
+		},
+		{
+			desc:     "results over the cap",
+			queries:  []string{"capped"},
+			expected: []string{"traefik/capped-1"},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			scrapper := NewScrapper(newSearchClient(t), nil, nil, false, nil, test.queries, nil)
+
+			repositories, complete, err := scrapper.search(context.Background())
+			require.NoError(t, err)
+
+			var names []string
+			for _, repository := range repositories {
+				names = append(names, repository.GetFullName())
+			}
+
+			assert.Equal(t, test.expected, names)
+			assert.Equal(t, test.expectedComplete, complete)
+		})
+	}
+}
+
+func TestScrapper_Run_incompleteSearch(t *testing.T) {
+	var deprecated []string
+	pgClient := &mockPluginClient{
+		list: func() ([]plugin.Plugin, error) {
+			return []plugin.Plugin{{ID: "1", Name: "github.com/traefik/plugin-a", Author: "traefik", RepoName: "plugin-a"}}, nil
+		},
+		deprecate: func(p plugin.Plugin) error {
+			deprecated = append(deprecated, p.Name)
+			return nil
+		},
+	}
+
+	scrapper := NewScrapper(newSearchClient(t), nil, pgClient, false, nil, []string{"incomplete"}, nil, WithReconciliation(ReconcileDeprecate, 1))
+
+	_, err := scrapper.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, deprecated)
+}
+
+// newSearchClient returns a GitHub client on a search API: the repositories are named like the query, with a page per repository.
+// The "paged" query has 2 pages, the "incomplete" query reports incomplete results, and the "capped" query exceeds the results cap.
+func newSearchClient(t *testing.T) *github.Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query().Get("q")
+
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		page = max(page, 1)
+
+		pages := 1
+		if query == "paged" {
+			pages = 2
+		}
+
+		result := &github.RepositoriesSearchResult{
+			Total:             github.Int(pages),
+			IncompleteResults: github.Bool(query == "incomplete"),
+		}
+
+		if query == "capped" {
+			result.Total = github.Int(searchResultsCap + 1)
+		}
+
+		if page <= pages {
+			result.Repositories = []*github.Repository{{FullName: github.String(fmt.Sprintf("traefik/%s-%d", query, page))}}
+		}
+
+		if page < pages {
+			next := *req.URL
+			values := next.Query()
+			values.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = values.Encode()
+			rw.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(result)
+	}))
+	t.Cleanup(server.Close)
+
+	client := github.NewClient(nil)
+
+	var err error
+	client.BaseURL, err = url.Parse(server.URL + "/")
+	require.NoError(t, err)
+
+	return client
+}
@@ -0,0 +1,116 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunRepository analyzes a single repository.
+// The repository is skipped if it doesn't match the search queries (ex: archived, without the plugin topic).
+func (s *Scrapper) RunRepository(ctx context.Context, owner, name string) (Result, error) {
+	ctx, span := s.tracer.Start(ctx, "scrapper_run_repository")
+	defer span.End()
+
+	qualifier := "repo:" + owner + "/" + name
+
+	reposWithExistingIssue, err := s.searchReposWithExistingIssue(ctx, qualifier)
+	if err != nil {
+		span.RecordError(err)
+		s.metrics.failure(ctx, newError(CodeSearchFailed, err))
+		return Result{}, err
+	}
+
+	repositories, _, err := s.search(ctx, qualifier)
+	if err != nil {
+		span.RecordError(err)
+		s.metrics.failure(ctx, newError(CodeSearchFailed, err))
+		return Result{}, err
+	}
+
+	for _, repository := range repositories {
+		// The search can return repositories with a similar name.
+		if !strings.EqualFold(repository.GetFullName(), owner+"/"+name) {
+			continue
+		}
+
+		s.metrics.repositoriesDiscovered(ctx, 1)
+
+		return s.analyze(ctx, reposWithExistingIssue, repository)
+	}
+
+	log.Ctx(ctx).Debug().Str("repo_name", owner+"/"+name).Msg("The repository doesn't match the search queries")
+
+	result := Result{
+		Repository: owner + "/" + name,
+		Date:       time.Now().UTC(),
+		Outcome:    OutcomeSkipped,
+		SkipReason: SkipReasonNotMatching,
+	}
+
+	s.setResult(result)
+	s.metrics.repositorySkipped(ctx, SkipReasonNotMatching)
+
+	return result, nil
+}
+
+// ReconcileRepository reconciles the catalog entries of a repository which cannot be analyzed anymore (ex: archived, renamed):
+// the entries are deprecated or deleted, according to the reconcile mode.
+func (s *Scrapper) ReconcileRepository(ctx context.Context, owner, name string) (Result, error) {
+	ctx, span := s.tracer.Start(ctx, "scrapper_reconcile_repository")
+	defer span.End()
+
+	result := Result{
+		Repository: owner + "/" + name,
+		Date:       time.Now().UTC(),
+		Outcome:    OutcomeUnchanged,
+	}
+
+	if s.reconcileMode == "" || s.reconcileMode == ReconcileNone {
+		s.setResult(result)
+		return result, nil
+	}
+
+	plugins, err := s.pg.List(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return Result{}, fmt.Errorf("failed to list plugins: %w", err)
+	}
+
+	for _, p := range plugins {
+		if !strings.EqualFold(p.Author+"/"+p.RepoName, owner+"/"+name) {
+			continue
+		}
+
+		if s.reconcileMode == ReconcileDeprecate && p.Deprecated {
+			continue
+		}
+
+		logger := log.Ctx(ctx).With().Str("module_name", p.Name).Str("reconcile_mode", s.reconcileMode).Logger()
+
+		result.Plugin = p.Name
+		result.Version = p.LatestVersion
+
+		if s.dryRun {
+			logger.Info().Msg("Dry run, not reconciling the plugin")
+			continue
+		}
+
+		err = s.reconcilePlugin(ctx, p)
+		if err != nil {
+			span.RecordError(err)
+			return Result{}, fmt.Errorf("failed to reconcile %s: %w", p.Name, err)
+		}
+
+		logger.Info().Msg("Reconciled")
+
+		result.Outcome = OutcomeReconciled
+	}
+
+	s.setResult(result)
+
+	return result, nil
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Reasons to skip the analysis of a repository.
+const (
+	// SkipReasonBlocklisted the repository is in the blocklist.
+	SkipReasonBlocklisted = "blocklisted"
+	// SkipReasonOpenIssue the analyzer issue is still opened.
+	SkipReasonOpenIssue = "open_issue"
+	// SkipReasonNotMatching the repository doesn't match the search queries.
+	SkipReasonNotMatching = "not_matching"
+)
+
+// WarningCode the stable identifier of an analysis warning.
+type WarningCode string
+
+// Codes of the analysis warnings.
+const (
+	// WarningAssetMissing the icon or the banner doesn't exist at the analyzed version.
+	WarningAssetMissing WarningCode = "asset_missing"
+	// WarningAssetInvalid the icon or the banner is not a valid image (type, size, dimensions).
+	WarningAssetInvalid WarningCode = "asset_invalid"
+	// WarningAssetUnavailable the icon or the banner cannot be fetched.
+	WarningAssetUnavailable WarningCode = "asset_unavailable"
+	// WarningAssetSanitized the active content of an SVG icon or banner has been removed.
+	WarningAssetSanitized WarningCode = "asset_sanitized"
+	// WarningSnippetOmitted the labels, tags or flags snippets cannot be generated from the test data.
+	WarningSnippetOmitted WarningCode = "snippet_omitted"
+	// WarningConfigSchema the JSON schema of the configuration cannot be generated.
+	WarningConfigSchema WarningCode = "config_schema"
+	// WarningConfigDecoding a field of the configuration is decoded differently from the labels or the Kubernetes CRDs.
+	WarningConfigDecoding WarningCode = "config_decoding"
+)
+
+// Warning a problem which doesn't prevent the import of the plugin.
+type Warning struct {
+	Code    WarningCode `json:"code"`
+	Message string      `json:"message"`
+}
+
+func newWarning(code WarningCode, format string, a ...any) Warning {
+	return Warning{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+// Result the result of the analysis of a repository.
+type Result struct {
+	Repository string    `json:"repository"`
+	Date       time.Time `json:"date"`
+	Outcome    string    `json:"outcome"`
+	SkipReason string    `json:"skipReason,omitempty"`
+	// Report the cause of the failure.
+	Report   string   `json:"report,omitempty"`
+	Code     Code     `json:"code,omitempty"`
+	Category Category `json:"category,omitempty"`
+	IssueURL string   `json:"issueUrl,omitempty"`
+	Plugin   string   `json:"plugin,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	// Warnings the problems which don't prevent the import of the plugin.
+	Warnings []Warning `json:"warnings,omitempty"`
+}
+
+// Result returns the result of the last analysis of a repository.
+func (s *Scrapper) Result(owner, name string) (Result, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result, ok := s.results[repositoryKey(owner+"/"+name)]
+
+	return result, ok
+}
+
+// Block adds a repository (owner/name) to the blocklist, the repository will be skipped by the next analyses.
+// The blocklist is in-memory: the blocked repositories are lost on restart.
+func (s *Scrapper) Block(fullName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blacklist[repositoryKey(fullName)] = struct{}{}
+}
+
+func (s *Scrapper) setResult(result Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.results[repositoryKey(result.Repository)] = result
+}
+
+// repositoryKey the key of a repository in the results and the blocklist: the GitHub names are case-insensitive.
+func repositoryKey(fullName string) string {
+	return strings.ToLower(fullName)
+}
@@ -0,0 +1,358 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	pfile "github.com/traefik/paerser/file"
+	"github.com/traefik/paerser/flag"
+	"github.com/traefik/paerser/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// pluginConf the configuration of a plugin, like in Traefik.
+type pluginConf map[string]interface{}
+
+// dynamicConfig the subset of the Traefik dynamic configuration used by the middleware snippets.
+type dynamicConfig struct {
+	HTTP *struct {
+		Middlewares map[string]*struct {
+			Plugin map[string]pluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+		} `json:"middlewares,omitempty" toml:"middlewares,omitempty" yaml:"middlewares,omitempty"`
+	} `json:"http,omitempty" toml:"http,omitempty" yaml:"http,omitempty"`
+}
+
+func (c dynamicConfig) plugin(middleware, name string) pluginConf {
+	if c.HTTP == nil || c.HTTP.Middlewares[middleware] == nil {
+		return nil
+	}
+
+	return c.HTTP.Middlewares[middleware].Plugin[name]
+}
+
+// staticConfig the subset of the Traefik static configuration used by the provider and install snippets.
+type staticConfig struct {
+	Providers *struct {
+		Plugin map[string]pluginConf `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+	} `json:"providers,omitempty" toml:"providers,omitempty" yaml:"providers,omitempty"`
+	Experimental *struct {
+		Plugins map[string]*struct {
+			ModuleName string `json:"moduleName,omitempty" toml:"moduleName,omitempty" yaml:"moduleName,omitempty"`
+			Version    string `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+		} `json:"plugins,omitempty" toml:"plugins,omitempty" yaml:"plugins,omitempty"`
+		LocalPlugins map[string]*struct {
+			ModuleName string `json:"moduleName,omitempty" toml:"moduleName,omitempty" yaml:"moduleName,omitempty"`
+		} `json:"localPlugins,omitempty" toml:"localPlugins,omitempty" yaml:"localPlugins,omitempty"`
+	} `json:"experimental,omitempty" toml:"experimental,omitempty" yaml:"experimental,omitempty"`
+}
+
+// checkSnippets decodes the snippets like Traefik (file, labels, tags and flags),
+// and compares the configuration of the plugin with the test data of the manifest.
+// The omitted snippets (see createSnippets) are not checked.
+func checkSnippets(repository *github.Repository, manifest Manifest, moduleName, version string, snippets map[string]interface{}) error {
+	name := repository.GetName()
+
+	var configs map[string]pluginConf
+	var err error
+
+	switch manifest.Type {
+	case typeMiddleware:
+		configs, err = decodeMiddlewareSnippets(name, snippets)
+	case typeProvider:
+		configs, err = decodeProviderSnippets(name, snippets)
+	default:
+		return fmt.Errorf("unsupported type: %s", manifest.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, format := range sortedKeys(configs) {
+		diff, err := diffConfig(format, manifest.TestData, configs[format])
+		if err != nil {
+			return fmt.Errorf("the %s snippet: %w", format, err)
+		}
+
+		if len(diff) > 0 {
+			return fmt.Errorf("the %s snippet is not decoded to the testData: %s", format, strings.Join(diff, ", "))
+		}
+	}
+
+	install, ok := snippets["install"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	return checkInstallSnippets(name, moduleName, version, install)
+}
+
+func decodeMiddlewareSnippets(name string, snippets map[string]interface{}) (map[string]pluginConf, error) {
+	middleware := "my-" + name
+	configs := make(map[string]pluginConf)
+
+	for _, format := range []string{"toml", "yaml"} {
+		var cfg dynamicConfig
+		err := pfile.DecodeContent(snippetString(snippets, format), "."+format, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the %s snippet: %w", format, err)
+		}
+
+		configs[format] = cfg.plugin(middleware, name)
+	}
+
+	// The Kubernetes resources are decoded as JSON, the scalar types are kept.
+	var k8s struct {
+		Spec struct {
+			Plugin map[string]pluginConf `yaml:"plugin"`
+		} `yaml:"spec"`
+	}
+	err := yaml.Unmarshal([]byte(snippetString(snippets, "k8s")), &k8s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the k8s snippet: %w", err)
+	}
+
+	configs["k8s"] = k8s.Spec.Plugin[name]
+
+	// The labels and the tags are omitted if the test data cannot be flattened.
+	if _, ok := snippets["docker"]; !ok {
+		return configs, nil
+	}
+
+	var docker struct {
+		Labels map[string]string `yaml:"labels"`
+	}
+	err = yaml.Unmarshal([]byte(snippetString(snippets, "docker")), &docker)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the docker snippet: %w", err)
+	}
+
+	tags, err := parseHCLTags(snippetString(snippets, "tags"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the tags snippet: %w", err)
+	}
+
+	for format, labels := range map[string]map[string]string{"docker": docker.Labels, "tags": tags} {
+		if len(labels) == 0 {
+			configs[format] = nil
+			continue
+		}
+
+		var cfg dynamicConfig
+		err = parser.Decode(labels, &cfg, labelRoot, labelRoot+".http")
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the %s snippet: %w", format, err)
+		}
+
+		configs[format] = cfg.plugin(middleware, name)
+	}
+
+	return configs, nil
+}
+
+func decodeProviderSnippets(name string, snippets map[string]interface{}) (map[string]pluginConf, error) {
+	configs := make(map[string]pluginConf)
+
+	for _, format := range []string{"toml", "yaml", "cli"} {
+		// The flags are omitted if the test data cannot be flattened.
+		if _, ok := snippets[format]; !ok {
+			continue
+		}
+
+		cfg, err := decodeStaticSnippet(format, snippetString(snippets, format))
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Providers != nil {
+			configs[format] = cfg.Providers.Plugin[name]
+		} else {
+			configs[format] = nil
+		}
+	}
+
+	return configs, nil
+}
+
+func checkInstallSnippets(name, moduleName, version string, install map[string]interface{}) error {
+	for _, format := range []string{"toml", "yaml", "cli", "helm"} {
+		cfg, err := decodeStaticSnippet(format, snippetString(install, format))
+		if err != nil {
+			return fmt.Errorf("install: %w", err)
+		}
+
+		if cfg.Experimental == nil || cfg.Experimental.Plugins[name] == nil {
+			return fmt.Errorf("the install %s snippet doesn't contain the plugin", format)
+		}
+
+		p := cfg.Experimental.Plugins[name]
+		if p.ModuleName != moduleName || p.Version != version {
+			return fmt.Errorf("the install %s snippet is decoded to %s@%s instead of %s@%s", format, p.ModuleName, p.Version, moduleName, version)
+		}
+	}
+
+	local, ok := install["local"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, format := range []string{"toml", "yaml", "cli"} {
+		cfg, err := decodeStaticSnippet(format, snippetString(local, format))
+		if err != nil {
+			return fmt.Errorf("local install: %w", err)
+		}
+
+		if cfg.Experimental == nil || cfg.Experimental.LocalPlugins[name] == nil {
+			return fmt.Errorf("the local install %s snippet doesn't contain the plugin", format)
+		}
+
+		if p := cfg.Experimental.LocalPlugins[name]; p.ModuleName != moduleName {
+			return fmt.Errorf("the local install %s snippet is decoded to %s instead of %s", format, p.ModuleName, moduleName)
+		}
+	}
+
+	return nil
+}
+
+// decodeStaticSnippet decodes a static configuration snippet: a file (TOML, YAML, Helm values) or CLI flags.
+func decodeStaticSnippet(format, snippet string) (staticConfig, error) {
+	var cfg staticConfig
+
+	var err error
+	switch format {
+	case "cli":
+		var args []string
+		args, err = parseCLIFlags(snippet)
+		if err == nil {
+			err = flag.Decode(args, &cfg)
+		}
+	case "helm":
+		err = pfile.DecodeContent(snippet, ".yaml", &cfg)
+	default:
+		err = pfile.DecodeContent(snippet, "."+format, &cfg)
+	}
+	if err != nil {
+		return staticConfig{}, fmt.Errorf("failed to decode the %s snippet: %w", format, err)
+	}
+
+	return cfg, nil
+}
+
+// diffConfig compares the configuration decoded from a snippet with the test data.
+// The files and the Kubernetes resources keep the types of the test data: the values are compared.
+// The labels, the tags and the flags are strings: the configurations are compared flattened (see diffFlattenedConfig).
+func diffConfig(format string, testData map[string]interface{}, cfg pluginConf) ([]string, error) {
+	switch format {
+	case "docker", "tags", "cli":
+		return diffFlattenedConfig(testData, cfg)
+	default:
+		return diffConfigValues("testData", reflect.ValueOf(testData), reflect.ValueOf(map[string]interface{}(cfg))), nil
+	}
+}
+
+// diffFlattenedConfig compares a decoded configuration with the test data, flattened like in labels:
+// the scalar types, and the empty values (nil, empty maps and slices) are ignored.
+func diffFlattenedConfig(testData map[string]interface{}, cfg pluginConf) ([]string, error) {
+	expected := make(map[string]string)
+	err := flattenValue(expected, "testData", reflect.ValueOf(testData))
+	if err != nil {
+		return nil, err
+	}
+
+	actual := make(map[string]string)
+	err = flattenValue(actual, "testData", reflect.ValueOf(map[string]interface{}(cfg)))
+	if err != nil {
+		return nil, err
+	}
+
+	var diff []string
+	for _, key := range sortedKeys(expected) {
+		value, ok := actual[key]
+		switch {
+		case !ok:
+			diff = append(diff, key+" is missing")
+		case value != expected[key]:
+			diff = append(diff, fmt.Sprintf("%s is %q instead of %q", key, value, expected[key]))
+		}
+	}
+
+	for _, key := range sortedKeys(actual) {
+		if _, ok := expected[key]; !ok {
+			diff = append(diff, key+" is unexpected")
+		}
+	}
+
+	return diff, nil
+}
+
+// parseCLIFlags parses the flags of a CLI snippet (one `--name=value` per line, the values can be single-quoted).
+func parseCLIFlags(snippet string) ([]string, error) {
+	var args []string
+
+	for _, line := range strings.Split(strings.TrimSpace(snippet), "\n") {
+		if line == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(line, "=")
+		if !found || !strings.HasPrefix(name, "--") {
+			return nil, fmt.Errorf("invalid flag: %s", line)
+		}
+
+		if strings.HasPrefix(value, "'") {
+			if len(value) < 2 || !strings.HasSuffix(value, "'") {
+				return nil, fmt.Errorf("invalid quoted value: %s", line)
+			}
+
+			value = strings.ReplaceAll(value[1:len(value)-1], `'\''`, "'")
+		}
+
+		args = append(args, name+"="+value)
+	}
+
+	return args, nil
+}
+
+// parseHCLTags parses the tags of a tags snippet as labels.
+func parseHCLTags(snippet string) (map[string]string, error) {
+	lines := strings.Split(strings.TrimSpace(snippet), "\n")
+	if len(lines) < 2 || lines[0] != "tags = [" || lines[len(lines)-1] != "]" {
+		return nil, errors.New("invalid tags list")
+	}
+
+	labels := make(map[string]string)
+
+	for _, line := range lines[1 : len(lines)-1] {
+		tag, err := strconv.Unquote(strings.TrimSuffix(strings.TrimSpace(line), ","))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag %s: %w", line, err)
+		}
+
+		tag = strings.NewReplacer("$${", "${", "%%{", "%{").Replace(tag)
+
+		key, value, _ := strings.Cut(tag, "=")
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+func snippetString(snippets map[string]interface{}, format string) string {
+	s, _ := snippets[format].(string)
+	return s
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
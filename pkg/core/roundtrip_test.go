@@ -0,0 +1,223 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pfile "github.com/traefik/paerser/file"
+)
+
+func Test_checkSnippets(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		repoName string
+		typ      string
+		testData string
+		tamper   func(snippets map[string]interface{})
+		expected string
+	}{
+		{
+			desc:     "middleware",
+			repoName: "PluginTest",
+			typ:      typeMiddleware,
+			testData: `
+Headers:
+  Foo: Bar
+Values: [1, 2.5, "a b"]
+Items:
+  - Name: ${name}
+Enabled: true
+Empty: []
+Quote: it's "quoted"
+`,
+		},
+		{
+			desc:     "provider",
+			repoName: "plugintest",
+			typ:      typeProvider,
+			testData: `
+PollInterval: 2s
+Values: [a, it's]
+`,
+		},
+		{
+			desc:     "middleware key which cannot be flattened",
+			repoName: "plugintest",
+			typ:      typeMiddleware,
+			testData: `
+Headers:
+  X.Foo: bar
+`,
+		},
+		{
+			desc:     "provider key which cannot be flattened",
+			repoName: "plugintest",
+			typ:      typeProvider,
+			testData: `
+Servers:
+  "a=b": c
+`,
+		},
+		{
+			desc:     "keys differing only by their case",
+			repoName: "plugintest",
+			typ:      typeMiddleware,
+			testData: `
+foo: a
+Foo: b
+`,
+			expected: "the docker snippet is not decoded to the testData",
+		},
+		{
+			desc:     "tampered snippet",
+			repoName: "plugintest",
+			typ:      typeProvider,
+			testData: "foo: bar",
+			tamper: func(snippets map[string]interface{}) {
+				snippets["cli"] = "--providers.plugin.plugintest.foo=baz\n"
+			},
+			expected: `the cli snippet is not decoded to the testData: testData.foo is "baz" instead of "bar"`,
+		},
+		{
+			desc:     "tampered k8s snippet",
+			repoName: "plugintest",
+			typ:      typeMiddleware,
+			testData: `count: "1"`,
+			tamper: func(snippets map[string]interface{}) {
+				snippets["k8s"] = strings.Replace(snippets["k8s"].(string), `count: "1"`, "count: 1", 1)
+			},
+			expected: `the k8s snippet is not decoded to the testData: testData[count] is 1 instead of "1"`,
+		},
+		{
+			desc:     "tampered install snippet",
+			repoName: "plugintest",
+			typ:      typeProvider,
+			testData: "foo: bar",
+			tamper: func(snippets map[string]interface{}) {
+				install := snippets["install"].(map[string]interface{})
+				install["helm"] = strings.Replace(install["helm"].(string), "v1.0.0", "v0.1.0", 1)
+			},
+			expected: "the install helm snippet is decoded to github.com/traefik/plugintest@v0.1.0 instead of github.com/traefik/plugintest@v1.0.0",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			repository := &github.Repository{Name: github.String(test.repoName)}
+
+			// The test data is decoded like the manifest.
+			manifest := Manifest{Type: test.typ}
+			err := pfile.DecodeContent("testData:\n"+indent(test.testData), ".yaml", &manifest)
+			require.NoError(t, err)
+
+			snippets, _, err := createSnippets(repository, manifest)
+			require.NoError(t, err)
+
+			snippets["install"], err = createInstallSnippets(repository, "github.com/traefik/"+test.repoName, "v1.0.0")
+			require.NoError(t, err)
+
+			if test.tamper != nil {
+				test.tamper(snippets)
+			}
+
+			err = checkSnippets(repository, manifest, "github.com/traefik/"+test.repoName, "v1.0.0", snippets)
+			if test.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expected)
+		})
+	}
+}
+
+func Test_diffConfig(t *testing.T) {
+	testData := map[string]interface{}{
+		"a": "1",
+		"b": []interface{}{"x", "y"},
+		"c": map[string]interface{}{},
+	}
+
+	cfg := pluginConf{
+		"a": 1,
+		"b": []interface{}{"x"},
+		"d": "z",
+	}
+
+	diff, err := diffConfig("docker", testData, cfg)
+	require.NoError(t, err)
+
+	expected := []string{"testData.b[1] is missing", "testData.d is unexpected"}
+	assert.Equal(t, expected, diff)
+}
+
+func Test_diffConfig_typed(t *testing.T) {
+	testData := map[string]interface{}{
+		"a": "1",
+		"b": []interface{}{"x", "y"},
+		"c": map[string]interface{}{},
+		"e": map[string]interface{}{"f": "true"},
+	}
+
+	cfg := pluginConf{
+		"a": 1,
+		"b": []interface{}{"x", "y"},
+		"e": map[string]interface{}{"f": true},
+	}
+
+	// The flattened values are the same.
+	diff, err := diffConfig("cli", testData, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	diff, err = diffConfig("yaml", testData, cfg)
+	require.NoError(t, err)
+
+	expected := []string{`testData[a] is 1 instead of "1"`, `testData[e][f] is true instead of "true"`}
+	assert.Equal(t, expected, diff)
+}
+
+func Test_parseCLIFlags(t *testing.T) {
+	labels := map[string]string{
+		"providers.plugin.test.a": "it's a value",
+		"providers.plugin.test.b": "",
+		"providers.plugin.test.c": "foo",
+	}
+
+	args, err := parseCLIFlags(cliFlags(labels))
+	require.NoError(t, err)
+
+	expected := []string{
+		"--providers.plugin.test.a=it's a value",
+		"--providers.plugin.test.b=",
+		"--providers.plugin.test.c=foo",
+	}
+	assert.Equal(t, expected, args)
+}
+
+func Test_parseHCLTags(t *testing.T) {
+	labels := map[string]string{
+		"traefik.plugin.a": `${var} %{if} "quoted"`,
+		"traefik.plugin.b": "a=b",
+	}
+
+	tags, err := parseHCLTags(hclTags(labels))
+	require.NoError(t, err)
+
+	assert.Equal(t, labels, tags)
+}
+
+func indent(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = "  " + line
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/ldez/grignotin/goproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/internal/fakegithub"
+	"github.com/traefik/piceus/internal/plugin"
+	"github.com/traefik/piceus/internal/stub/service"
+	"github.com/traefik/piceus/pkg/feed"
+	"github.com/traefik/piceus/pkg/sources"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestScrapper_Run(t *testing.T) {
+	repositories := []fakegithub.Repository{
+		{
+			Owner:  "traefik",
+			Name:   "plugintestsimple",
+			Topics: []string{"traefik-plugin"},
+			Stars:  10,
+			Tags:   []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+		{
+			Owner:  "traefik",
+			Name:   "plugintestunsafe",
+			Topics: []string{"traefik-plugin", hiddenTopic},
+			Tags:   []fakegithub.Tag{{Name: "v1.0.0", Dir: filepath.Join("fixtures", "unsafe")}},
+		},
+		{
+			Owner:  "traefik",
+			Name:   "plugintestwrongunsafe",
+			Topics: []string{"traefik-plugin"},
+			Tags:   []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "wrongunsafe")}},
+		},
+		{
+			Owner:  "traefik",
+			Name:   "plugintestinvalidtag",
+			Topics: []string{"traefik-plugin"},
+			Tags:   []fakegithub.Tag{{Name: "1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+		{
+			Owner:  "traefik",
+			Name:   "plugintestwithissue",
+			Topics: []string{"traefik-plugin"},
+			Tags:   []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "wrongunsafe")}},
+		},
+		{
+			Owner:    "traefik",
+			Name:     "plugintestarchived",
+			Topics:   []string{"traefik-plugin"},
+			Archived: true,
+			Tags:     []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+		{
+			Owner: "traefik",
+			Name:  "notaplugin",
+			Tags:  []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+	}
+
+	fake, err := fakegithub.NewServer(repositories...)
+	require.NoError(t, err)
+	t.Cleanup(fake.Close)
+
+	fake.AddIssue("traefik", "plugintestwithissue", issueTitle)
+
+	svc, err := service.New()
+	require.NoError(t, err)
+
+	pgServer := httptest.NewServer(svc)
+	t.Cleanup(pgServer.Close)
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, err = url.Parse(fake.URL())
+	require.NoError(t, err)
+
+	gpClient := goproxy.NewClient(fake.GoProxyURL())
+
+	events, err := feed.NewLog(filepath.Join(t.TempDir(), "events.json"), 10)
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+
+	scrapper := NewScrapper(ghClient, gpClient, plugin.New(pgServer.URL+"/"), false, &sources.GoProxy{Client: gpClient},
+		[]string{"topic:traefik-plugin language:Go archived:false is:public"},
+		[]string{"is:open is:issue is:public author:traefiker"},
+		WithEventRecorder(events),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	stats, err := scrapper.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Repositories: 5, Skipped: 1, Failed: 2, Created: 2}, stats)
+
+	stored := svc.Plugins()
+	require.Len(t, stored, 2)
+
+	assert.Equal(t, "github.com/traefik/plugintestsimple", stored[0].Name)
+	assert.Equal(t, "v0.1.0", stored[0].LatestVersion)
+	assert.Equal(t, []string{"v0.1.0"}, stored[0].Versions)
+	assert.Equal(t, 10, stored[0].Stars)
+	assert.Contains(t, stored[0].Readme, "# Plugin Without Unsafe")
+	assert.False(t, stored[0].Hidden)
+	assert.Equal(t, fake.URL()+"traefik/plugintestsimple/raw/v0.1.0/icon.png", stored[0].IconURL)
+	assert.Empty(t, stored[0].BannerURL)
+
+	result, ok := scrapper.Result("traefik", "plugintestsimple")
+	require.True(t, ok)
+	assert.Equal(t, []Warning{{Code: WarningAssetMissing, Message: `the banner "banner.png" doesn't exist at v0.1.0`}}, result.Warnings)
+
+	assert.Equal(t, "github.com/traefik/plugintestunsafe", stored[1].Name)
+	assert.Equal(t, "v1.0.0", stored[1].LatestVersion)
+	assert.True(t, stored[1].UseUnsafe)
+	assert.True(t, stored[1].Hidden)
+
+	var added []string
+	for _, event := range events.Events() {
+		assert.Equal(t, feed.EventAdded, event.Type)
+		assert.Equal(t, fake.URL()+"traefik/plugintestsimple", event.URL)
+		added = append(added, event.ID())
+	}
+	// The hidden plugins are not in the feeds.
+	assert.Equal(t, []string{"github.com/traefik/plugintestsimple@v0.1.0"}, added)
+
+	issues := fake.CreatedIssues()
+	require.Len(t, issues, 2)
+
+	assert.Equal(t, fake.URL()+"repos/traefik/plugintestinvalidtag", issues[0].GetRepositoryURL())
+	assert.Equal(t, issueTitle, issues[0].GetTitle())
+	assert.Contains(t, issues[0].GetBody(), "invalid tag: 1.0")
+
+	assert.Equal(t, fake.URL()+"repos/traefik/plugintestwrongunsafe", issues[1].GetRepositoryURL())
+	assert.Equal(t, issueTitle, issues[1].GetTitle())
+	assert.Contains(t, issues[1].GetBody(), "failed to run the plugin with Yaegi")
+
+	var rm metricdata.ResourceMetrics
+	err = reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{"": 5}, sums(t, rm, "piceus.repositories.discovered"))
+	assert.Equal(t, map[string]int64{SkipReasonOpenIssue: 1}, sums(t, rm, "piceus.repositories.skipped"))
+	assert.Equal(t, map[string]int64{OutcomeCreated: 2}, sums(t, rm, "piceus.plugins.stored"))
+	assert.Equal(t, map[string]int64{string(CategoryVersioning): 1, string(CategoryYaegi): 1}, sums(t, rm, "piceus.failures"))
+	assert.Equal(t, map[string]int64{"": 2}, sums(t, rm, "piceus.issues.opened"))
+
+	checks := histogramCounts(t, rm, "piceus.yaegi.check.duration")
+	assert.Equal(t, map[string]uint64{"success": 2, "failure": 1}, checks)
+
+	// The plugins already up to date are unchanged, and not checked again.
+	_, err = scrapper.Run(context.Background())
+	require.NoError(t, err)
+
+	rm = metricdata.ResourceMetrics{}
+	err = reader.Collect(context.Background(), &rm)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int64{OutcomeCreated: 2, OutcomeUnchanged: 2}, sums(t, rm, "piceus.plugins.stored"))
+	assert.Equal(t, map[string]uint64{"success": 2, "failure": 1}, histogramCounts(t, rm, "piceus.yaegi.check.duration"))
+}
+
+func TestScrapper_RunRepository(t *testing.T) {
+	fake, err := fakegithub.NewServer(
+		fakegithub.Repository{
+			Owner:  "traefik",
+			Name:   "plugintestsimple",
+			Topics: []string{"traefik-plugin"},
+			Tags:   []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+		fakegithub.Repository{
+			Owner:  "traefik",
+			Name:   "plugintestwrongunsafe",
+			Topics: []string{"traefik-plugin"},
+			Tags:   []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "wrongunsafe")}},
+		},
+		fakegithub.Repository{
+			Owner: "traefik",
+			Name:  "notaplugin",
+			Tags:  []fakegithub.Tag{{Name: "v0.1.0", Dir: filepath.Join("fixtures", "simple")}},
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(fake.Close)
+
+	fake.AddIssue("traefik", "plugintestwrongunsafe", issueTitle)
+
+	svc, err := service.New()
+	require.NoError(t, err)
+
+	pgServer := httptest.NewServer(svc)
+	t.Cleanup(pgServer.Close)
+
+	ghClient := github.NewClient(nil)
+	ghClient.BaseURL, err = url.Parse(fake.URL())
+	require.NoError(t, err)
+
+	gpClient := goproxy.NewClient(fake.GoProxyURL())
+
+	scrapper := NewScrapper(ghClient, gpClient, plugin.New(pgServer.URL+"/"), false, &sources.GoProxy{Client: gpClient},
+		[]string{"topic:traefik-plugin language:Go archived:false is:public"},
+		[]string{"is:open is:issue is:public author:traefiker"})
+
+	ctx := context.Background()
+
+	result, err := scrapper.RunRepository(ctx, "traefik", "notaplugin")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSkipped, result.Outcome)
+	assert.Equal(t, SkipReasonNotMatching, result.SkipReason)
+	assert.Empty(t, svc.Plugins())
+
+	result, err = scrapper.RunRepository(ctx, "traefik", "plugintestsimple")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeCreated, result.Outcome)
+	assert.Equal(t, "github.com/traefik/plugintestsimple", result.Plugin)
+	assert.Equal(t, "v0.1.0", result.Version)
+
+	stored := svc.Plugins()
+	require.Len(t, stored, 1)
+	assert.Equal(t, "github.com/traefik/plugintestsimple", stored[0].Name)
+
+	last, ok := scrapper.Result("Traefik", "PluginTestSimple")
+	require.True(t, ok)
+	assert.Equal(t, result, last)
+
+	// The analyzer issue is still opened.
+	result, err = scrapper.RunRepository(ctx, "traefik", "plugintestwrongunsafe")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSkipped, result.Outcome)
+	assert.Equal(t, SkipReasonOpenIssue, result.SkipReason)
+	assert.NotEmpty(t, result.IssueURL)
+	assert.Empty(t, fake.CreatedIssues())
+
+	fake.CloseIssues("traefik", "plugintestwrongunsafe")
+
+	result, err = scrapper.RunRepository(ctx, "traefik", "plugintestwrongunsafe")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeFailed, result.Outcome)
+	assert.Equal(t, CodeYaegiLoad, result.Code)
+	assert.Equal(t, CategoryYaegi, result.Category)
+	assert.Contains(t, result.Report, "failed to run the plugin with Yaegi")
+
+	issues := fake.CreatedIssues()
+	require.Len(t, issues, 1)
+	assert.Equal(t, fake.URL()+"repos/traefik/plugintestwrongunsafe", issues[0].GetRepositoryURL())
+
+	// The blocklist is case-insensitive.
+	scrapper.Block("Traefik/PluginTestSimple")
+
+	result, err = scrapper.RunRepository(ctx, "traefik", "plugintestsimple")
+	require.NoError(t, err)
+	assert.Equal(t, OutcomeSkipped, result.Outcome)
+	assert.Equal(t, SkipReasonBlocklisted, result.SkipReason)
+}
+
+// sums returns the values of a counter by the value of its attribute (empty if none).
+func sums(t *testing.T, rm metricdata.ResourceMetrics, name string) map[string]int64 {
+	t.Helper()
+
+	values := make(map[string]int64)
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+
+			for _, dp := range sum.DataPoints {
+				values[attributeValue(dp.Attributes)] += dp.Value
+			}
+		}
+	}
+
+	return values
+}
+
+// histogramCounts returns the number of values recorded by a histogram by the value of its attribute (empty if none).
+func histogramCounts(t *testing.T, rm metricdata.ResourceMetrics, name string) map[string]uint64 {
+	t.Helper()
+
+	counts := make(map[string]uint64)
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			histogram, ok := m.Data.(metricdata.Histogram[float64])
+			require.True(t, ok)
+
+			for _, dp := range histogram.DataPoints {
+				counts[attributeValue(dp.Attributes)] += dp.Count
+			}
+		}
+	}
+
+	return counts
+}
+
+func attributeValue(set attribute.Set) string {
+	iter := set.Iter()
+	if !iter.Next() {
+		return ""
+	}
+
+	return iter.Attribute().Value.Emit()
+}
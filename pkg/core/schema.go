@@ -0,0 +1,415 @@
+package core
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// configDocs the declarations of the types of a plugin package, used to get the doc comments of the configuration.
+type configDocs struct {
+	types map[string]*ast.TypeSpec
+	// docs the doc comments of the types, by type name.
+	docs map[string]string
+	// config the result type of the CreateConfig function.
+	config ast.Expr
+}
+
+// parseConfigDocs parses the Go sources of a plugin package.
+// The files that cannot be parsed are ignored: the doc comments are optional.
+func parseConfigDocs(dir string) configDocs {
+	docs := configDocs{
+		types: make(map[string]*ast.TypeSpec),
+		docs:  make(map[string]string),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return docs
+	}
+
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+
+					docs.types[typeSpec.Name.Name] = typeSpec
+
+					// The doc of a single type declaration is on the declaration.
+					doc := typeSpec.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					docs.docs[typeSpec.Name.Name] = commentText(doc)
+				}
+
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.Name == "CreateConfig" && d.Type.Results != nil && len(d.Type.Results.List) > 0 {
+					docs.config = d.Type.Results.List[0].Type
+				}
+			}
+		}
+	}
+
+	return docs
+}
+
+// resolve returns the type declaration of an expression, and the doc of the named type.
+func (d configDocs) resolve(expr ast.Expr) (ast.Expr, string) {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ParenExpr:
+			expr = e.X
+		case *ast.Ident:
+			spec, ok := d.types[e.Name]
+			if !ok {
+				return nil, ""
+			}
+
+			return spec.Type, d.docs[e.Name]
+		default:
+			return expr, ""
+		}
+	}
+}
+
+// configSchema generates the JSON schema of the plugin configuration returned by CreateConfig:
+// the non-zero values of the configuration are the defaults, the descriptions are the doc comments.
+func configSchema(vConfig reflect.Value, docs configDocs) map[string]interface{} {
+	b := schemaBuilder{docs: docs, visiting: make(map[reflect.Type]bool)}
+
+	schema := b.schema(vConfig.Type(), vConfig, docs.config)
+	if schema == nil {
+		return nil
+	}
+
+	schema["$schema"] = jsonSchemaDialect
+
+	return schema
+}
+
+// safeConfigSchema generates the JSON schema of the plugin configuration.
+// The schema is optional: a panic during the generation (ex: a value interpreted by Yaegi) is reported as a warning, without schema.
+func safeConfigSchema(vConfig reflect.Value, docs configDocs) (schema map[string]interface{}, warnings []Warning) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			schema = nil
+			warnings = []Warning{newWarning(WarningConfigSchema, "failed to generate the configuration schema: %v", rec)}
+		}
+	}()
+
+	return configSchema(vConfig, docs), nil
+}
+
+type schemaBuilder struct {
+	docs configDocs
+	// visiting the struct types being generated, to stop on the recursive types.
+	visiting map[reflect.Type]bool
+}
+
+// schema generates the schema of a type.
+// The value is the default value (can be invalid), the expression is the declaration of the type in the sources (can be nil).
+// It returns nil for the types that cannot be decoded (ex: functions, channels).
+func (b schemaBuilder) schema(rType reflect.Type, value reflect.Value, expr ast.Expr) map[string]interface{} {
+	for rType.Kind() == reflect.Pointer {
+		rType = rType.Elem()
+
+		if value.IsValid() && !value.IsNil() {
+			value = value.Elem()
+		} else {
+			value = reflect.Value{}
+		}
+	}
+
+	expr, doc := b.docs.resolve(expr)
+
+	schema := map[string]interface{}{}
+	if doc != "" {
+		schema["description"] = doc
+	}
+
+	switch rType.Kind() {
+	case reflect.Bool:
+		schema["type"] = "boolean"
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		schema["type"] = "integer"
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		schema["type"] = "integer"
+		schema["minimum"] = 0
+
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+
+	case reflect.String:
+		schema["type"] = "string"
+
+	case reflect.Interface:
+		// Any value.
+
+	case reflect.Slice, reflect.Array:
+		if rType.Kind() == reflect.Slice && rType.Elem().Kind() == reflect.Uint8 {
+			schema["type"] = "string"
+			break
+		}
+
+		var elt ast.Expr
+		if arrayType, ok := expr.(*ast.ArrayType); ok {
+			elt = arrayType.Elt
+		}
+
+		items := b.schema(rType.Elem(), reflect.Value{}, elt)
+		if items == nil {
+			return nil
+		}
+
+		schema["type"] = "array"
+		schema["items"] = items
+
+	case reflect.Map:
+		var elt ast.Expr
+		if mapType, ok := expr.(*ast.MapType); ok {
+			elt = mapType.Value
+		}
+
+		values := b.schema(rType.Elem(), reflect.Value{}, elt)
+		if values == nil {
+			return nil
+		}
+
+		schema["type"] = "object"
+		schema["additionalProperties"] = values
+
+	case reflect.Struct:
+		if b.visiting[rType] {
+			schema["type"] = "object"
+			return schema
+		}
+
+		b.visiting[rType] = true
+		defer delete(b.visiting, rType)
+
+		structType, _ := expr.(*ast.StructType)
+
+		schema["type"] = "object"
+		schema["properties"] = b.properties(rType, value, structType)
+
+		return schema
+
+	default:
+		return nil
+	}
+
+	if def := defaultValue(value); def != nil {
+		schema["default"] = def
+	}
+
+	return schema
+}
+
+// properties generates the properties of a struct, named like mapstructure decodes them.
+func (b schemaBuilder) properties(rType reflect.Type, value reflect.Value, structType *ast.StructType) map[string]interface{} {
+	fields := structFields(structType)
+
+	properties := map[string]interface{}{}
+
+	for i := range rType.NumField() {
+		field := rType.Field(i)
+		if !field.IsExported() || isYaegiUnexported(field) {
+			continue
+		}
+
+		name, squash := mapstructureName(field)
+		if name == "-" {
+			continue
+		}
+
+		var fieldValue reflect.Value
+		if value.IsValid() {
+			fieldValue = value.Field(i)
+		}
+
+		var fieldExpr ast.Expr
+		var fieldDoc string
+		if astField, ok := fields[field.Name]; ok {
+			fieldExpr = astField.Type
+			fieldDoc = commentText(astField.Doc)
+			if fieldDoc == "" {
+				fieldDoc = commentText(astField.Comment)
+			}
+		}
+
+		if squash && field.Type.Kind() == reflect.Struct {
+			expr, _ := b.docs.resolve(fieldExpr)
+			structExpr, _ := expr.(*ast.StructType)
+
+			for k, v := range b.properties(field.Type, fieldValue, structExpr) {
+				properties[k] = v
+			}
+
+			continue
+		}
+
+		schema := b.schema(field.Type, fieldValue, fieldExpr)
+		if schema == nil {
+			continue
+		}
+
+		if fieldDoc != "" {
+			schema["description"] = fieldDoc
+		}
+
+		properties[name] = schema
+	}
+
+	return properties
+}
+
+// isYaegiUnexported returns true for the unexported fields of a struct interpreted by Yaegi: they are renamed with an X prefix.
+func isYaegiUnexported(field reflect.StructField) bool {
+	name, found := strings.CutPrefix(field.Name, "X")
+	return found && name != "" && !ast.IsExported(name)
+}
+
+// structFields returns the fields of a struct declaration, by name.
+func structFields(structType *ast.StructType) map[string]*ast.Field {
+	fields := make(map[string]*ast.Field)
+	if structType == nil || structType.Fields == nil {
+		return fields
+	}
+
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 0 {
+			// Embedded field: the name is the name of the type.
+			expr := field.Type
+			if star, ok := expr.(*ast.StarExpr); ok {
+				expr = star.X
+			}
+
+			switch e := expr.(type) {
+			case *ast.Ident:
+				fields[e.Name] = field
+			case *ast.SelectorExpr:
+				fields[e.Sel.Name] = field
+			}
+
+			continue
+		}
+
+		for _, name := range field.Names {
+			fields[name.Name] = field
+		}
+	}
+
+	return fields
+}
+
+// mapstructureName returns the name of a field in the configuration, and true if the field is squashed.
+func mapstructureName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("mapstructure")
+	if !ok {
+		return field.Name, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	squash := field.Anonymous && strings.Contains(","+opts+",", ",squash,")
+
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, squash
+}
+
+// defaultValue returns the JSON value of a default value, or nil if the value is empty.
+func defaultValue(value reflect.Value) interface{} {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || value.IsZero() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint()
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.String:
+		return value.String()
+
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8 {
+			return string(value.Bytes())
+		}
+
+		if value.Len() == 0 {
+			return nil
+		}
+
+		values := make([]interface{}, 0, value.Len())
+		for i := range value.Len() {
+			values = append(values, defaultValue(value.Index(i)))
+		}
+
+		return values
+
+	case reflect.Map:
+		if value.Len() == 0 {
+			return nil
+		}
+
+		values := make(map[string]interface{}, value.Len())
+		for _, key := range value.MapKeys() {
+			values[formatScalar(key)] = defaultValue(value.MapIndex(key))
+		}
+
+		return values
+
+	default:
+		return nil
+	}
+}
+
+func commentText(group *ast.CommentGroup) string {
+	if group == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(group.Text())
+}
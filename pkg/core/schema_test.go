@@ -0,0 +1,168 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/yaegi/interp"
+	"github.com/traefik/yaegi/stdlib"
+)
+
+const schemaPluginSource = `package plugin
+
+// Config the plugin configuration.
+type Config struct {
+	// Name the name of the header.
+	Name    string ` + "`json:\"name,omitempty\"`" + `
+	secret  string
+	Enabled bool
+	Ratio   float64
+	Port    uint16
+	Values  []string
+	Headers map[string]string // Headers the headers to add.
+	Rules   []Rule
+	Next    *Config
+	Ignored func()
+}
+
+// Rule a rule.
+type Rule struct {
+	// Path the path prefix.
+	Path string
+}
+
+// CreateConfig creates the default plugin configuration.
+func CreateConfig() *Config {
+	return &Config{
+		Name:    "X-Foo",
+		Ratio:   0.5,
+		Values:  []string{"a"},
+		Headers: map[string]string{"X-Bar": "bar"},
+	}
+}
+`
+
+func Test_configSchema(t *testing.T) {
+	goPath := t.TempDir()
+	dir := filepath.Join(goPath, "src", "example.com", "plugin")
+
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.go"), []byte(schemaPluginSource), 0o600))
+
+	i := interp.New(interp.Options{GoPath: goPath})
+	require.NoError(t, i.Use(stdlib.Symbols))
+
+	_, err := i.Eval(`import "example.com/plugin"`)
+	require.NoError(t, err)
+
+	vConfig, err := i.Eval(`plugin.CreateConfig()`)
+	require.NoError(t, err)
+
+	schema := configSchema(vConfig, parseConfigDocs(dir))
+
+	expected := map[string]interface{}{
+		"$schema":     jsonSchemaDialect,
+		"description": "Config the plugin configuration.",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"Name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name the name of the header.",
+				"default":     "X-Foo",
+			},
+			"Enabled": map[string]interface{}{
+				"type": "boolean",
+			},
+			"Ratio": map[string]interface{}{
+				"type":    "number",
+				"default": 0.5,
+			},
+			"Port": map[string]interface{}{
+				"type":    "integer",
+				"minimum": 0,
+			},
+			"Values": map[string]interface{}{
+				"type":    "array",
+				"items":   map[string]interface{}{"type": "string"},
+				"default": []interface{}{"a"},
+			},
+			"Headers": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Headers the headers to add.",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+				"default":              map[string]interface{}{"X-Bar": "bar"},
+			},
+			"Rules": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":        "object",
+					"description": "Rule a rule.",
+					"properties": map[string]interface{}{
+						"Path": map[string]interface{}{
+							"type":        "string",
+							"description": "Path the path prefix.",
+						},
+					},
+				},
+			},
+			"Next": map[string]interface{}{
+				"type":        "object",
+				"description": "Config the plugin configuration.",
+			},
+		},
+	}
+
+	assert.Equal(t, expected, schema)
+}
+
+func Test_safeConfigSchema(t *testing.T) {
+	type config struct {
+		Name string
+	}
+
+	schema, warnings := safeConfigSchema(reflect.ValueOf(&config{}), configDocs{})
+	assert.NotNil(t, schema)
+	assert.Empty(t, warnings)
+
+	// reflect.Value.Type panics on the zero value.
+	schema, warnings = safeConfigSchema(reflect.Value{}, configDocs{})
+	assert.Nil(t, schema)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, WarningConfigSchema, warnings[0].Code)
+}
+
+func Test_configSchema_withoutSources(t *testing.T) {
+	type Base struct {
+		Level string
+	}
+
+	type config struct {
+		Base    `mapstructure:",squash"`
+		Name    string `mapstructure:"name"`
+		Skipped string `mapstructure:"-"`
+		Any     interface{}
+	}
+
+	schema := configSchema(reflect.ValueOf(&config{Base: Base{Level: "info"}}), configDocs{})
+
+	expected := map[string]interface{}{
+		"$schema": jsonSchemaDialect,
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"Level": map[string]interface{}{
+				"type":    "string",
+				"default": "info",
+			},
+			"name": map[string]interface{}{
+				"type": "string",
+			},
+			"Any": map[string]interface{}{},
+		},
+	}
+
+	assert.Equal(t, expected, schema)
+}
@@ -12,6 +12,9 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -21,7 +24,9 @@ import (
 	"github.com/rs/zerolog/log"
 	pfile "github.com/traefik/paerser/file"
 	"github.com/traefik/piceus/internal/plugin"
+	"github.com/traefik/piceus/pkg/feed"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	oteltrace "go.opentelemetry.io/otel/trace"
 	"gopkg.in/yaml.v3"
 )
@@ -35,6 +40,11 @@ const wasmRuntime = "wasm"
 
 const hiddenTopic = "traefik-plugin-hidden"
 
+const defaultModuleHost = "github.com"
+
+// searchResultsCap the maximum number of results returned by the GitHub search API for a query.
+const searchResultsCap = 1000
+
 const (
 	typeMiddleware = "middleware"
 	typeProvider   = "provider"
@@ -43,29 +53,23 @@ const (
 const (
 	oldIssueTitle = "[Traefik Pilot] Traefik Plugin Analyzer has detected a problem." // must be keep forever.
 	issueTitle    = "[Traefik Plugin Catalog] Plugin Analyzer has detected a problem."
-	issueContent  = `The plugin was not imported into Traefik Plugin Catalog.
-
-Cause:
-` + "```" + `
-%v
-` + "```" + `
-Traefik Plugin Analyzer will restart when you will close this issue.
-
-If you believe there is a problem with the Analyzer or this issue is the result of a false positive, please fill an issue on [piceus](https://github.com/traefik/piceus) repository.
-`
 )
 
-type pluginClient interface {
+// PluginClient stores the plugins into the catalog.
+type PluginClient interface {
 	Create(ctx context.Context, p plugin.Plugin) error
 	Update(ctx context.Context, p plugin.Plugin) error
 	GetByName(ctx context.Context, name string) (*plugin.Plugin, error)
+	List(ctx context.Context) ([]plugin.Plugin, error)
+	Delete(ctx context.Context, id string) error
+	Deprecate(ctx context.Context, p plugin.Plugin) error
 }
 
 // Scrapper the plugins scrapper.
 type Scrapper struct {
 	gh *github.Client
 	gp *goproxy.Client
-	pg pluginClient
+	pg PluginClient
 
 	dryRun bool
 
@@ -76,11 +80,107 @@ type Scrapper struct {
 	blacklist   map[string]struct{}
 	skipNewCall map[string]struct{} // temporary approach
 	tracer      oteltrace.Tracer
+
+	meterProvider metric.MeterProvider
+	metrics       *metrics
+
+	// mu protects blacklist and results.
+	mu      sync.RWMutex
+	results map[string]Result
+
+	// moduleHost is the host prefix of the plugin module names (ex: github.com).
+	moduleHost string
+
+	reconcileMode     string
+	reconcileMaxRatio float64
+
+	events EventRecorder
+
+	issueTemplate *template.Template
+
+	readmeHTML bool
+
+	mirror *assetMirror
+
+	configDecoding string
+}
+
+// EventRecorder records the catalog changes.
+type EventRecorder interface {
+	Record(event feed.Event)
+}
+
+// Option configures a Scrapper.
+type Option func(s *Scrapper)
+
+// WithModuleHost sets the host prefix of the plugin module names (ex: a GitHub Enterprise Server host).
+func WithModuleHost(host string) Option {
+	return func(s *Scrapper) {
+		if host != "" {
+			s.moduleHost = host
+		}
+	}
+}
+
+// WithReconciliation deprecates or deletes the plugins that disappeared from the search results.
+// The reconciliation is aborted if the ratio of plugins to reconcile is greater than maxRatio.
+func WithReconciliation(mode string, maxRatio float64) Option {
+	return func(s *Scrapper) {
+		s.reconcileMode = mode
+		s.reconcileMaxRatio = maxRatio
+	}
+}
+
+// WithEventRecorder records the added plugins and the new releases.
+func WithEventRecorder(recorder EventRecorder) Option {
+	return func(s *Scrapper) {
+		s.events = recorder
+	}
+}
+
+// WithMeterProvider sets the provider of the domain metrics (defaults to the global provider).
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(s *Scrapper) {
+		s.meterProvider = provider
+	}
+}
+
+// WithIssueTemplate sets the template of the analyzer issues body (see ParseIssueTemplate).
+func WithIssueTemplate(tmpl *template.Template) Option {
+	return func(s *Scrapper) {
+		if tmpl != nil {
+			s.issueTemplate = tmpl
+		}
+	}
+}
+
+// WithReadmeHTML stores a pre-rendered HTML version and a table of contents of the README with the plugins.
+func WithReadmeHTML(enabled bool) Option {
+	return func(s *Scrapper) {
+		s.readmeHTML = enabled
+	}
+}
+
+// WithAssetMirror stores the icons and the banners into dir, under a content-hash name.
+// The plugins reference the images from baseURL, the URL where dir is served.
+func WithAssetMirror(dir string, baseURL *url.URL) Option {
+	return func(s *Scrapper) {
+		if dir != "" && baseURL != nil {
+			s.mirror = &assetMirror{dir: dir, baseURL: baseURL}
+		}
+	}
+}
+
+// WithConfigDecoding sets the decoding mode of the plugin configuration (mapstructure, traefik).
+func WithConfigDecoding(mode string) Option {
+	return func(s *Scrapper) {
+		s.configDecoding = mode
+	}
 }
 
 // NewScrapper creates a new Scrapper instance.
-func NewScrapper(gh *github.Client, gp *goproxy.Client, pgClient pluginClient, dryRun bool, sources Sources, searchQueries, searchQueriesIssues []string) *Scrapper {
-	return &Scrapper{
+func NewScrapper(gh *github.Client, gp *goproxy.Client, pgClient PluginClient, dryRun bool, sources Sources, searchQueries, searchQueriesIssues []string, opts ...Option) *Scrapper {
+	s := &Scrapper{
 		gh: gh,
 		gp: gp,
 		pg: pgClient,
@@ -92,6 +192,7 @@ func NewScrapper(gh *github.Client, gp *goproxy.Client, pgClient pluginClient, d
 
 		sources: sources,
 		// TODO improve blacklist storage
+		// The keys are lowercase (see repositoryKey).
 		blacklist: map[string]struct{}{
 			"containous/plugintestxxx":                  {},
 			"enzo24ofreopgh/traefik-maintenance-warden": {}, // Doesn't allow issues
@@ -101,104 +202,218 @@ func NewScrapper(gh *github.Client, gp *goproxy.Client, pgClient pluginClient, d
 			"thubolt/geoblock":                          {}, // Doesn't allow issues
 			"tmpim/tmpauth-traefik":                     {}, // Doesn't allow issues
 			"alexdelprete/traefik-oidc-relying-party":   {},
-			"FinalCAD/TraefikGrpcWebPlugin":             {}, // Crash piceus.
+			"finalcad/traefikgrpcwebplugin":             {}, // Crash piceus.
 			"deas/teectl":                               {}, // Not a plugin
-			"GDGVIT/securum-exire":                      {}, // Not a plugin
+			"gdgvit/securum-exire":                      {}, // Not a plugin
 			"morzan1001/forward_auth_grpc_plugin":       {}, // piceus panic (excluded during fix)
 			"iobear/queryparameter-to-bearer":           {}, // Doesn't allow issues
 		},
 		skipNewCall: map[string]struct{}{
 			"github.com/negasus/traefik-plugin-ip2location": {},
 		},
-		tracer: otel.GetTracerProvider().Tracer("scrapper"),
+		results:       make(map[string]Result),
+		tracer:        otel.GetTracerProvider().Tracer("scrapper"),
+		meterProvider: otel.GetMeterProvider(),
+		moduleHost:    defaultModuleHost,
+		issueTemplate: mustParseDefaultIssueTemplate(),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.metrics = newMetrics(s.meterProvider.Meter("piceus"))
+
+	return s
 }
 
 // Run runs the scrapper.
-func (s *Scrapper) Run(ctx context.Context) error {
+func (s *Scrapper) Run(ctx context.Context) (Stats, error) {
 	ctx, span := s.tracer.Start(ctx, "scrapper_run")
 	defer span.End()
 
+	var stats Stats
+
 	reposWithExistingIssue, err := s.searchReposWithExistingIssue(ctx)
 	if err != nil {
 		span.RecordError(err)
-		return err
+		s.metrics.failure(ctx, newError(CodeSearchFailed, err))
+		return stats, err
 	}
 
-	repositories, err := s.search(ctx)
+	repositories, complete, err := s.search(ctx)
 	if err != nil {
 		span.RecordError(err)
-		return err
+		s.metrics.failure(ctx, newError(CodeSearchFailed, err))
+		return stats, err
 	}
 
+	s.metrics.repositoriesDiscovered(ctx, len(repositories))
+
 	for _, repository := range repositories {
-		logger := log.With().Str("repo_name", repository.GetFullName()).Logger()
-		logger.Debug().Msg("Processing repository")
+		result, err := s.analyze(ctx, reposWithExistingIssue, repository)
+		stats.add(result.Outcome)
 
-		if s.isSkipped(logger.WithContext(ctx), reposWithExistingIssue, repository) {
-			continue
+		if err != nil {
+			span.RecordError(err)
+			return stats, err
 		}
+	}
 
-		data, err := s.process(logger.WithContext(ctx), repository)
-		if err != nil {
-			logger.Error().Err(err).Msg("Failed to import repository")
+	// A repository missing from incomplete results is not gone: the reconciliation would retire a valid plugin.
+	if !complete {
+		log.Ctx(ctx).Warn().Msg("The search results are incomplete, the reconciliation is skipped")
+		return stats, nil
+	}
 
-			errResp := &github.ErrorResponse{}
-			if errors.As(err, &errResp) && errResp.Response.StatusCode >= http.StatusInternalServerError {
-				span.RecordError(err)
-				continue
-			}
+	err = s.reconcile(ctx, repositories)
+	if err != nil {
+		span.RecordError(err)
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to reconcile the catalog")
+	}
 
-			issue := &github.IssueRequest{
-				Title: github.String(issueTitle),
-				Body:  github.String(safeIssueBody(err)),
-			}
+	return stats, nil
+}
 
-			if s.dryRun {
-				logger.Info().Msg("Dry run, not creating the issue")
-				logger.Debug().Interface("issue", issue).Send()
-				continue
-			}
+// analyze analyzes a repository, then stores the plugin or opens an issue.
+// Only the errors that must abort the run are returned.
+func (s *Scrapper) analyze(ctx context.Context, reposWithExistingIssue map[string]string, repository *github.Repository) (Result, error) {
+	ctx, span := s.tracer.Start(ctx, "scrapper_analyze")
+	defer span.End()
 
-			_, _, err = s.gh.Issues.Create(ctx, repository.GetOwner().GetLogin(), repository.GetName(), issue)
-			if err != nil {
-				span.RecordError(err)
-				logger.Error().Err(err).Msg("Failed to create issue")
-			}
+	logger := log.With().Str("repo_name", repository.GetFullName()).Logger()
+	logger.Debug().Msg("Processing repository")
 
-			continue
+	result := Result{Repository: repository.GetFullName(), Date: time.Now().UTC()}
+	defer func() { s.setResult(result) }()
+
+	if reason := s.skipReason(logger.WithContext(ctx), reposWithExistingIssue, repository); reason != "" {
+		result.Outcome = OutcomeSkipped
+		result.SkipReason = reason
+		result.IssueURL = reposWithExistingIssue[repository.GetFullName()]
+
+		s.metrics.repositorySkipped(ctx, reason)
+
+		return result, nil
+	}
+
+	data, warnings, err := s.process(logger.WithContext(ctx), repository)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to import repository")
+
+		aErr := AsError(err)
+
+		result.Report = err.Error()
+		result.Code = aErr.Code
+		result.Category = aErr.Category
+		result.Version = aErr.Version
+
+		s.metrics.failure(ctx, aErr)
+
+		if !aErr.Reportable() {
+			span.RecordError(err)
+			result.Outcome = OutcomeError
+			return result, nil
+		}
+
+		result.Outcome = OutcomeFailed
+
+		body, err := s.issueBody(logger.WithContext(ctx), err)
+		if err != nil {
+			span.RecordError(err)
+			logger.Error().Err(err).Msg("Failed to render issue")
+			return result, nil
+		}
+
+		issue := &github.IssueRequest{
+			Title: github.String(issueTitle),
+			Body:  github.String(body),
 		}
 
 		if s.dryRun {
-			logger.Info().Msg("Dry run, not storing the plugin")
-			logger.Debug().Interface("data", data).Send()
-			continue
+			logger.Info().Msg("Dry run, not creating the issue")
+			logger.Debug().Interface("issue", issue).Send()
+			return result, nil
 		}
 
-		err = s.store(logger.WithContext(ctx), data)
+		created, _, err := s.gh.Issues.Create(ctx, repository.GetOwner().GetLogin(), repository.GetName(), issue)
 		if err != nil {
 			span.RecordError(err)
-			logger.Error().Err(err).Msg("Failed to store plugin")
+			logger.Error().Err(err).Msg("Failed to create issue")
+		} else {
+			s.metrics.issueOpened(ctx)
+		}
+
+		result.IssueURL = created.GetHTMLURL()
+
+		return result, nil
+	}
+
+	if data != nil {
+		result.Plugin = data.Name
+		result.Version = data.LatestVersion
+	}
+
+	result.Warnings = warnings
+	for _, warning := range warnings {
+		logger.Warn().Str("code", string(warning.Code)).Msg(warning.Message)
+	}
+
+	if s.dryRun {
+		logger.Info().Msg("Dry run, not storing the plugin")
+		logger.Debug().Interface("data", data).Send()
+		result.Outcome = OutcomeUnchanged
+		return result, nil
+	}
+
+	result.Outcome, err = s.store(logger.WithContext(ctx), repository, data)
+	if err != nil {
+		span.RecordError(err)
+		logger.Error().Err(err).Msg("Failed to store plugin")
+
+		aErr := newError(CodeStoreFailed, err)
+		s.metrics.failure(ctx, aErr)
+
+		result.Outcome = OutcomeError
+		result.Report = err.Error()
+		result.Code = aErr.Code
+		result.Category = aErr.Category
+
+		if errors.Is(err, plugin.ErrServiceUnavailable) {
+			return result, fmt.Errorf("aborting the run: %w", err)
 		}
+
+		return result, nil
 	}
 
-	return nil
+	// A plugin skipped because it is already up to date is unchanged.
+	s.metrics.pluginStored(ctx, result.Outcome)
+
+	return result, nil
 }
 
-func (s *Scrapper) isSkipped(ctx context.Context, reposWithExistingIssue []string, repository *github.Repository) bool {
-	if _, ok := s.blacklist[repository.GetFullName()]; ok {
-		return true
+// skipReason returns the reason why the repository must not be analyzed, or an empty string.
+func (s *Scrapper) skipReason(ctx context.Context, reposWithExistingIssue map[string]string, repository *github.Repository) string {
+	s.mu.RLock()
+	_, blocked := s.blacklist[repositoryKey(repository.GetFullName())]
+	s.mu.RUnlock()
+
+	if blocked {
+		return SkipReasonBlocklisted
 	}
 
-	if slices.Contains(reposWithExistingIssue, repository.GetFullName()) {
+	if _, ok := reposWithExistingIssue[repository.GetFullName()]; ok {
 		log.Ctx(ctx).Debug().Msg("The issue is still opened.")
-		return true
+		return SkipReasonOpenIssue
 	}
 
-	return false
+	return ""
 }
 
-func (s *Scrapper) searchReposWithExistingIssue(ctx context.Context) ([]string, error) {
+// searchReposWithExistingIssue searches the repositories with an opened analyzer issue.
+// It returns the URLs of the issues by repository full name.
+// The qualifiers (ex: "repo:owner/name") are added to the search queries.
+func (s *Scrapper) searchReposWithExistingIssue(ctx context.Context, qualifiers ...string) (map[string]string, error) {
 	opts := &github.SearchOptions{
 		Sort:        "updated",
 		ListOptions: github.ListOptions{PerPage: 100},
@@ -206,8 +421,13 @@ func (s *Scrapper) searchReposWithExistingIssue(ctx context.Context) ([]string,
 
 	log.Debug().Strs("searchQueriesIssues", s.searchQueriesIssues).Send()
 
-	var all []string
+	reposURL := s.gh.BaseURL.JoinPath("repos").String() + "/"
+
+	all := make(map[string]string)
 	for _, query := range s.searchQueriesIssues {
+		query = withQualifiers(query, qualifiers)
+		opts.Page = 0
+
 		for {
 			issues, resp, err := s.gh.Search.Issues(ctx, query, opts)
 			if err != nil {
@@ -215,9 +435,9 @@ func (s *Scrapper) searchReposWithExistingIssue(ctx context.Context) ([]string,
 			}
 
 			for _, issue := range issues.Issues {
-				if issue.GetTitle() == oldIssueTitle || issue.GetTitle() == issueTitle {
+				if IsAnalyzerIssue(issue.GetTitle()) {
 					// Creates the fullname of the repository.
-					all = append(all, strings.TrimPrefix(issue.GetRepositoryURL(), "https://api.github.com/repos/"))
+					all[strings.TrimPrefix(issue.GetRepositoryURL(), reposURL)] = issue.GetHTMLURL()
 				}
 			}
 
@@ -232,7 +452,11 @@ func (s *Scrapper) searchReposWithExistingIssue(ctx context.Context) ([]string,
 	return all, nil
 }
 
-func (s *Scrapper) search(ctx context.Context) ([]*github.Repository, error) {
+// search searches the plugin repositories.
+// The qualifiers (ex: "repo:owner/name") are added to the search queries.
+// It returns false if the results are incomplete: GitHub reported incomplete results (ex: timeout),
+// or a query matches more repositories than the search API can return.
+func (s *Scrapper) search(ctx context.Context, qualifiers ...string) ([]*github.Repository, bool, error) {
 	ctx, span := s.tracer.Start(ctx, "scrapper_search")
 	defer span.End()
 
@@ -244,13 +468,22 @@ func (s *Scrapper) search(ctx context.Context) ([]*github.Repository, error) {
 	log.Debug().Strs("searchQueries", s.searchQueries).Send()
 
 	var all []*github.Repository
+	complete := true
 
 	for _, query := range s.searchQueries {
+		query = withQualifiers(query, qualifiers)
+		opts.Page = 0
+
 		for {
 			repositories, resp, err := s.gh.Search.Repositories(ctx, query, opts)
 			if err != nil {
 				span.RecordError(err)
-				return nil, err
+				return nil, false, err
+			}
+
+			if repositories.GetIncompleteResults() || repositories.GetTotal() > searchResultsCap {
+				log.Ctx(ctx).Warn().Str("query", query).Int("total", repositories.GetTotal()).Msg("Incomplete search results")
+				complete = false
 			}
 
 			all = append(all, repositories.Repositories...)
@@ -262,25 +495,35 @@ func (s *Scrapper) search(ctx context.Context) ([]*github.Repository, error) {
 		}
 	}
 
-	return all, nil
+	return all, complete, nil
 }
 
-func (s *Scrapper) process(ctx context.Context, repository *github.Repository) (*plugin.Plugin, error) {
+func withQualifiers(query string, qualifiers []string) string {
+	if len(qualifiers) == 0 {
+		return query
+	}
+
+	return query + " " + strings.Join(qualifiers, " ")
+}
+
+func (s *Scrapper) process(ctx context.Context, repository *github.Repository) (_ *plugin.Plugin, _ []Warning, err error) {
 	ctx, span := s.tracer.Start(ctx, "scrapper_process_"+repository.GetName())
 	defer span.End()
 
 	latestVersion, err := s.getLatestTag(ctx, repository)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to get the latest tag: %w", err)
+		return nil, nil, fmt.Errorf("failed to get the latest tag: %w", err)
 	}
 
+	defer func() { err = withVersion(err, latestVersion) }()
+
 	// Gets readme
 
 	readme, err := s.loadReadme(ctx, repository, latestVersion)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to load readme: %w", err)
+		return nil, nil, fmt.Errorf("failed to load readme: %w", err)
 	}
 
 	// Gets manifestFile
@@ -288,42 +531,94 @@ func (s *Scrapper) process(ctx context.Context, repository *github.Repository) (
 	manifest, err := s.loadManifest(ctx, repository, latestVersion)
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, nil, err
 	}
 
 	var versions []string
 	var pluginName string
+	var warnings []Warning
+	var configSchema map[string]interface{}
+
+	start := time.Now()
 
 	switch manifest.Runtime {
 	case wasmRuntime:
 		pluginName, versions, err = s.verifyWASMPlugin(ctx, repository, latestVersion, manifest)
+		// No check is run when the plugin is already up to date.
+		if err != nil || pluginName != "" {
+			s.metrics.checkDuration(ctx, manifest.Runtime, start, err)
+		}
 		if err != nil {
 			span.RecordError(err)
-			return nil, err
+			return nil, nil, err
 		}
 
 		if pluginName == "" {
-			return nil, nil
+			return nil, nil, nil
 		}
 
 	default:
-		pluginName, versions, err = s.verifyYaegiPlugin(ctx, repository, latestVersion, manifest)
+		var result yaegiResult
+		pluginName, versions, result, err = s.verifyYaegiPlugin(ctx, repository, latestVersion, manifest)
+		// No check is run when the plugin is already up to date.
+		if err != nil || pluginName != "" {
+			s.metrics.checkDuration(ctx, manifest.Runtime, start, err)
+		}
 		if err != nil {
 			span.RecordError(err)
-			return nil, err
+			return nil, nil, err
 		}
 
+		warnings = result.warnings
+		configSchema = result.configSchema
+
 		if pluginName == "" {
-			return nil, nil
+			return nil, nil, nil
 		}
 	}
 
-	snippets, err := createSnippets(repository, manifest)
+	snippets, snippetWarnings, err := createSnippets(repository, manifest)
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, nil, err
 	}
 
+	warnings = append(warnings, snippetWarnings...)
+
+	snippets["install"], err = createInstallSnippets(repository, pluginName, latestVersion)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, err
+	}
+
+	err = checkSnippets(repository, manifest, pluginName, latestVersion, snippets)
+	if err != nil {
+		span.RecordError(err)
+		return nil, nil, newError(CodeManifestSnippet, err)
+	}
+
+	var readmeHTML string
+	var readmeTOC []plugin.Heading
+	if s.readmeHTML {
+		readmeHTML, readmeTOC, err = renderReadme(readme)
+		if err != nil {
+			span.RecordError(err)
+			return nil, nil, newError(CodeInternal, err)
+		}
+	}
+
+	icon, iconWarnings := s.loadAsset(ctx, repository, latestVersion, manifest.IconPath, iconSpec)
+	warnings = append(warnings, iconWarnings...)
+
+	banner, bannerWarnings := s.loadAsset(ctx, repository, latestVersion, manifest.BannerPath, bannerSpec)
+	warnings = append(warnings, bannerWarnings...)
+
+	iconURL, iconWarnings := s.assetURL(ctx, icon, iconSpec)
+	warnings = append(warnings, iconWarnings...)
+
+	bannerURL, bannerWarnings := s.assetURL(ctx, banner, bannerSpec)
+	warnings = append(warnings, bannerWarnings...)
+
 	return &plugin.Plugin{
 		Name:          pluginName,
 		DisplayName:   manifest.DisplayName,
@@ -334,16 +629,19 @@ func (s *Scrapper) process(ctx context.Context, repository *github.Repository) (
 		Import:        manifest.Import,
 		Compatibility: manifest.Compatibility,
 		Summary:       manifest.Summary,
-		IconURL:       parseImageURL(repository, latestVersion, manifest.IconPath),
-		BannerURL:     parseImageURL(repository, latestVersion, manifest.BannerPath),
+		IconURL:       iconURL,
+		BannerURL:     bannerURL,
 		Readme:        readme,
+		ReadmeHTML:    readmeHTML,
+		ReadmeTOC:     readmeTOC,
 		LatestVersion: latestVersion,
 		Versions:      versions,
 		Stars:         repository.GetStargazersCount(),
 		Snippet:       snippets,
+		ConfigSchema:  configSchema,
 		Hidden:        slices.Contains(repository.Topics, hiddenTopic),
 		UseUnsafe:     manifest.UseUnsafe,
-	}, nil
+	}, warnings, nil
 }
 
 func (s *Scrapper) loadManifest(ctx context.Context, repository *github.Repository, version string) (Manifest, error) {
@@ -355,18 +653,18 @@ func (s *Scrapper) loadManifest(ctx context.Context, repository *github.Reposito
 	contents, _, resp, err := s.gh.Repositories.GetContents(ctx, repository.GetOwner().GetLogin(), repository.GetName(), manifestFile, opts)
 	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		span.RecordError(fmt.Errorf("missing manifest: %w", err))
-		return Manifest{}, fmt.Errorf("missing manifest: %w", err)
+		return Manifest{}, newError(CodeManifestMissing, fmt.Errorf("missing manifest: %w", err))
 	}
 
 	if err != nil {
 		span.RecordError(err)
-		return Manifest{}, fmt.Errorf("failed to get manifest: %w", err)
+		return Manifest{}, newFetchError(CodeManifestMissing, fmt.Errorf("failed to get manifest: %w", err))
 	}
 
 	content, err := contents.GetContent()
 	if err != nil {
 		span.RecordError(err)
-		return Manifest{}, fmt.Errorf("failed to get manifest content: %w", err)
+		return Manifest{}, newError(CodeManifestInvalid, fmt.Errorf("failed to get manifest content: %w", err))
 	}
 
 	return s.loadManifestContent(content)
@@ -376,16 +674,17 @@ func (s *Scrapper) loadManifestContent(content string) (Manifest, error) {
 	var m Manifest
 	err := yaml.Unmarshal([]byte(content), &m)
 	if err != nil {
-		return Manifest{}, fmt.Errorf("failed to read manifest content: %w", err)
+		return Manifest{}, newError(CodeManifestInvalid, fmt.Errorf("failed to read manifest content: %w", err))
 	}
 
 	if len(m.TestData) > 0 {
 		var mp Manifest
 		err = pfile.DecodeContent(content, ".yaml", &mp)
 		if err != nil {
-			return Manifest{}, fmt.Errorf("failed to read testdata from manifest: %w", err)
+			return Manifest{}, newError(CodeManifestInvalid, fmt.Errorf("failed to read testdata from manifest: %w", err))
 		}
 
+		m.rawTestData = m.TestData
 		m.TestData = mp.TestData
 	}
 
@@ -394,26 +693,26 @@ func (s *Scrapper) loadManifestContent(content string) (Manifest, error) {
 	// noop
 	case typeProvider:
 		if m.Runtime == wasmRuntime {
-			return Manifest{}, fmt.Errorf("unsupported type for WASM plugin: %s", m.Type)
+			return Manifest{}, newError(CodeManifestUnsupportedType, fmt.Errorf("unsupported type for WASM plugin: %s", m.Type))
 		}
 	default:
-		return Manifest{}, fmt.Errorf("unsupported type: %s", m.Type)
+		return Manifest{}, newError(CodeManifestUnsupportedType, fmt.Errorf("unsupported type: %s", m.Type))
 	}
 
 	if m.Runtime != wasmRuntime && m.Import == "" {
-		return Manifest{}, errors.New("missing import")
+		return Manifest{}, newError(CodeManifestMissingField, errors.New("missing import"))
 	}
 
 	if m.DisplayName == "" {
-		return Manifest{}, errors.New("missing DisplayName")
+		return Manifest{}, newError(CodeManifestMissingField, errors.New("missing DisplayName"))
 	}
 
 	if m.Summary == "" {
-		return Manifest{}, errors.New("missing Summary")
+		return Manifest{}, newError(CodeManifestMissingField, errors.New("missing Summary"))
 	}
 
 	if m.TestData == nil {
-		return Manifest{}, errors.New("missing TestData")
+		return Manifest{}, newError(CodeManifestMissingField, errors.New("missing TestData"))
 	}
 
 	return m, nil
@@ -428,16 +727,36 @@ func (s *Scrapper) loadReadme(ctx context.Context, repository *github.Repository
 	readme, _, err := s.gh.Repositories.GetReadme(ctx, repository.GetOwner().GetLogin(), repository.GetName(), opts)
 	if err != nil {
 		span.RecordError(err)
-		return "", fmt.Errorf("failed to get the readme file: %w", err)
+		return "", newFetchError(CodeReadmeUnavailable, fmt.Errorf("failed to get the readme file: %w", err))
 	}
 
 	content, err := readme.GetContent()
 	if err != nil {
 		span.RecordError(err)
-		return "", fmt.Errorf("failed to get manifest content: %w", err)
+		return "", newError(CodeReadmeUnavailable, fmt.Errorf("failed to get manifest content: %w", err))
+	}
+
+	rawURL, err := rawContentURL(repository)
+	if err != nil {
+		span.RecordError(err)
+		return "", newError(CodeInternal, fmt.Errorf("failed to parse the repository URL: %w", err))
+	}
+
+	blobURL, err := url.Parse(strings.TrimSuffix(repository.GetHTMLURL(), "/"))
+	if err != nil {
+		span.RecordError(err)
+		return "", newError(CodeInternal, fmt.Errorf("failed to parse the repository URL: %w", err))
+	}
+
+	links := readmeLinks{
+		blobURL: blobURL.JoinPath("blob", version),
+		rawURL:  rawURL.JoinPath(version),
+		dir:     path.Dir(readme.GetPath()),
 	}
 
-	return content, nil
+	content = sanitizeReadme(links.rewrite(content))
+
+	return truncateReadme(content, maxReadmeSize, links.blobURL.JoinPath(readme.GetPath()).String()), nil
 }
 
 func (s *Scrapper) getLatestTag(ctx context.Context, repository *github.Repository) (string, error) {
@@ -451,7 +770,7 @@ func (s *Scrapper) getLatestTag(ctx context.Context, repository *github.Reposito
 	}
 
 	if len(tags) == 0 {
-		err := errors.New("missing tag/version")
+		err := newError(CodeVersionMissingTag, errors.New("missing tag/version"))
 		span.RecordError(err)
 		return "", err
 	}
@@ -474,11 +793,11 @@ func (s *Scrapper) getVersions(ctx context.Context, repository *github.Repositor
 
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, newFetchError(CodeModuleUnknownVersion, err)
 	}
 
 	if len(versions) == 0 {
-		err = errors.New("missing tag/version")
+		err = newError(CodeVersionMissingTag, errors.New("missing tag/version"))
 		span.RecordError(err)
 		return nil, err
 	}
@@ -493,7 +812,7 @@ func (s *Scrapper) getTags(ctx context.Context, repository *github.Repository) (
 	tags, _, err := s.gh.Repositories.ListTags(ctx, repository.GetOwner().GetLogin(), repository.GetName(), nil)
 	if err != nil {
 		span.RecordError(err)
-		return nil, fmt.Errorf("failed to get versions: %w", err)
+		return nil, newFetchError(CodeVersionMissingTag, fmt.Errorf("failed to get versions: %w", err))
 	}
 
 	expSemver := regexp.MustCompile(`^v(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
@@ -506,7 +825,7 @@ func (s *Scrapper) getTags(ctx context.Context, repository *github.Repository) (
 		}
 
 		if !expSemver.MatchString(name) {
-			return nil, fmt.Errorf("invalid tag: %s (this tag must be removed, see https://go.dev/doc/modules/version-numbers)", name)
+			return nil, newError(CodeVersionInvalidTag, fmt.Errorf("invalid tag: %s (this tag must be removed, see https://go.dev/doc/modules/version-numbers)", name))
 		}
 
 		result = append(result, name)
@@ -515,9 +834,20 @@ func (s *Scrapper) getTags(ctx context.Context, repository *github.Repository) (
 	return result, nil
 }
 
-func (s *Scrapper) store(ctx context.Context, data *plugin.Plugin) error {
+// isUnchanged returns true if the catalog plugin is up-to-date with the repository.
+// A deprecated plugin is never unchanged: it must be restored.
+// A renamed or transferred repository is never unchanged: the old name is reconciled.
+func isUnchanged(prev *plugin.Plugin, repository *github.Repository, latestVersion string) bool {
+	return prev != nil && !prev.Deprecated &&
+		prev.LatestVersion == latestVersion &&
+		prev.Stars == repository.GetStargazersCount() &&
+		prev.Author == repository.GetOwner().GetLogin() &&
+		prev.RepoName == repository.GetName()
+}
+
+func (s *Scrapper) store(ctx context.Context, repository *github.Repository, data *plugin.Plugin) (string, error) {
 	if data == nil {
-		return nil
+		return OutcomeUnchanged, nil
 	}
 
 	logger := log.Ctx(ctx).With().Str("module_name", data.Name).Logger()
@@ -535,18 +865,20 @@ func (s *Scrapper) store(ctx context.Context, data *plugin.Plugin) error {
 
 			err = s.pg.Create(ctx, *data)
 			if err != nil {
-				return err
+				return "", err
 			}
 
 			logger.Info().Msg("Stored")
-			return nil
+			s.recordEvent(feed.EventAdded, repository, data)
+
+			return OutcomeCreated, nil
 		}
 
-		return fmt.Errorf("API error on %s: %w", data.Name, err)
+		return "", fmt.Errorf("API error on %s: %w", data.Name, err)
 	}
 
 	if cmp.Equal(data, prev, cmpopts.IgnoreFields(plugin.Plugin{}, "ID", "CreatedAt")) {
-		return nil
+		return OutcomeUnchanged, nil
 	}
 
 	data.ID = prev.ID
@@ -555,28 +887,50 @@ func (s *Scrapper) store(ctx context.Context, data *plugin.Plugin) error {
 	err = s.pg.Update(ctx, *data)
 	if err != nil {
 		span.RecordError(err)
-		return err
+		return "", err
 	}
 
 	if prev.LatestVersion != data.LatestVersion {
 		logger.Info().Str("latest_version", data.LatestVersion).Msg("Updated")
+		s.recordEvent(feed.EventReleased, repository, data)
 	}
 
-	return nil
+	return OutcomeUpdated, nil
 }
 
-func createSnippets(repository *github.Repository, manifest Manifest) (map[string]interface{}, error) {
+// recordEvent records a catalog change in the feeds.
+// The hidden plugins are not listed in the catalog: they are not in the feeds either.
+func (s *Scrapper) recordEvent(eventType string, repository *github.Repository, data *plugin.Plugin) {
+	if s.events == nil || data.Hidden {
+		return
+	}
+
+	s.events.Record(feed.Event{
+		Type:        eventType,
+		Name:        data.Name,
+		DisplayName: data.DisplayName,
+		Summary:     data.Summary,
+		Author:      data.Author,
+		Version:     data.LatestVersion,
+		IconURL:     data.IconURL,
+		URL:         repository.GetHTMLURL(),
+	})
+}
+
+// createSnippets creates the configuration snippets of the plugin from the test data.
+// The labels, tags and flags snippets are omitted, with a warning, if a key of the test data cannot be flattened.
+func createSnippets(repository *github.Repository, manifest Manifest) (map[string]interface{}, []Warning, error) {
 	switch manifest.Type {
 	case typeMiddleware:
 		return createMiddlewareSnippets(repository, manifest.TestData)
 	case typeProvider:
 		return createProviderSnippets(repository, manifest.TestData)
 	default:
-		return nil, fmt.Errorf("unsupported type: %s", manifest.Type)
+		return nil, nil, fmt.Errorf("unsupported type: %s", manifest.Type)
 	}
 }
 
-func createMiddlewareSnippets(repository *github.Repository, testData map[string]interface{}) (map[string]interface{}, error) {
+func createMiddlewareSnippets(repository *github.Repository, testData map[string]interface{}) (map[string]interface{}, []Warning, error) {
 	snip := map[string]interface{}{
 		"http": map[string]interface{}{
 			"middlewares": map[string]interface{}{
@@ -591,12 +945,12 @@ func createMiddlewareSnippets(repository *github.Repository, testData map[string
 
 	yamlSnip, err := yaml.Marshal(snip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
 	}
 
 	tomlSnip, err := toml.Marshal(snip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
 	}
 
 	k8s := map[string]interface{}{
@@ -614,17 +968,72 @@ func createMiddlewareSnippets(repository *github.Repository, testData map[string
 	}
 	k8sSnip, err := yaml.Marshal(k8s)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
 	}
 
-	return map[string]interface{}{
-		"toml": string(tomlSnip),
-		"yaml": string(yamlSnip),
-		"k8s":  string(k8sSnip),
-	}, nil
+	gateway := map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata": map[string]interface{}{
+			"name":      "my-route",
+			"namespace": "my-namespace",
+		},
+		"spec": map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{"name": "my-gateway"},
+			},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"filters": []interface{}{
+						map[string]interface{}{
+							"type": "ExtensionRef",
+							"extensionRef": map[string]interface{}{
+								"group": "traefik.io",
+								"kind":  "Middleware",
+								"name":  "my-" + repository.GetName(),
+							},
+						},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{"name": "my-service", "port": 80},
+					},
+				},
+			},
+		},
+	}
+	gatewaySnip, err := yaml.Marshal(gateway)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+	}
+
+	snippets := map[string]interface{}{
+		"toml":    string(tomlSnip),
+		"yaml":    string(yamlSnip),
+		"k8s":     string(k8sSnip),
+		"gateway": string(gatewaySnip),
+	}
+
+	labels, err := flattenTestData(labelRoot+".http.middlewares.my-"+repository.GetName()+".plugin."+repository.GetName(), testData)
+	if err != nil {
+		if !errors.As(err, &labelKeyError{}) {
+			return nil, nil, newError(CodeManifestSnippet, fmt.Errorf("failed to flatten the test data: %w", err))
+		}
+
+		return snippets, []Warning{newWarning(WarningSnippetOmitted, "the docker and tags snippets are omitted: %v", err)}, nil
+	}
+
+	dockerSnip, err := yaml.Marshal(map[string]interface{}{"labels": labels})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+	}
+
+	snippets["docker"] = string(dockerSnip)
+	snippets["tags"] = hclTags(labels)
+
+	return snippets, nil, nil
 }
 
-func createProviderSnippets(repository *github.Repository, testData map[string]interface{}) (map[string]interface{}, error) {
+func createProviderSnippets(repository *github.Repository, testData map[string]interface{}) (map[string]interface{}, []Warning, error) {
 	snip := map[string]interface{}{
 		"providers": map[string]interface{}{
 			"plugin": map[string]interface{}{
@@ -635,18 +1044,32 @@ func createProviderSnippets(repository *github.Repository, testData map[string]i
 
 	yamlSnip, err := yaml.Marshal(snip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
 	}
 
 	tomlSnip, err := toml.Marshal(snip)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+		return nil, nil, fmt.Errorf("failed to marshall (YAML): %w", err)
 	}
 
-	return map[string]interface{}{
+	snippets := map[string]interface{}{
 		"toml": string(tomlSnip),
 		"yaml": string(yamlSnip),
-	}, nil
+	}
+
+	// The providers are in the static configuration: the labels are not supported.
+	flags, err := flattenTestData("providers.plugin."+repository.GetName(), testData)
+	if err != nil {
+		if !errors.As(err, &labelKeyError{}) {
+			return nil, nil, newError(CodeManifestSnippet, fmt.Errorf("failed to flatten the test data: %w", err))
+		}
+
+		return snippets, []Warning{newWarning(WarningSnippetOmitted, "the cli snippet is omitted: %v", err)}, nil
+	}
+
+	snippets["cli"] = cliFlags(flags)
+
+	return snippets, nil, nil
 }
 
 func parseImageURL(repository *github.Repository, latestVersion, imgPath string) string {
@@ -659,12 +1082,16 @@ func parseImageURL(repository *github.Repository, latestVersion, imgPath string)
 		return ""
 	}
 
-	rawURL := fmt.Sprintf("https://raw.githubusercontent.com/%s/%s", repository.GetOwner().GetLogin(), repository.GetName())
-	if strings.HasPrefix(imgPath, rawURL) {
+	baseURL, err := rawContentURL(repository)
+	if err != nil {
+		return ""
+	}
+
+	if strings.HasPrefix(imgPath, baseURL.String()) {
 		return imgPath
 	}
 
-	rawURL = fmt.Sprintf("https://github.com/%s/%s/raw/", repository.GetOwner().GetLogin(), repository.GetName())
+	rawURL := strings.TrimSuffix(repository.GetHTMLURL(), "/") + "/raw/"
 	if strings.HasPrefix(imgPath, rawURL) {
 		return imgPath
 	}
@@ -673,22 +1100,34 @@ func parseImageURL(repository *github.Repository, latestVersion, imgPath string)
 		return ""
 	}
 
+	return baseURL.JoinPath(latestVersion, path.Clean(img.Path)).String()
+}
+
+// rawContentURL returns the base URL of the raw contents of a repository.
+// GitHub serves them from raw.githubusercontent.com, GitHub Enterprise Server from the "raw" path of the repository.
+func rawContentURL(repository *github.Repository) (*url.URL, error) {
 	baseURL, err := url.Parse(repository.GetHTMLURL())
 	if err != nil {
-		return ""
+		return nil, err
 	}
 
-	baseURL.Host = "raw.githubusercontent.com"
+	baseURL.Path = strings.TrimSuffix(baseURL.Path, "/")
 
-	pictURL, err := baseURL.Parse(path.Join(baseURL.Path, latestVersion, path.Clean(img.Path)))
-	if err != nil {
-		return ""
+	if baseURL.Host == defaultModuleHost {
+		baseURL.Host = "raw.githubusercontent.com"
+		return baseURL, nil
 	}
 
-	return pictURL.String()
+	return baseURL.JoinPath("raw"), nil
+}
+
+// IsAnalyzerIssue returns true if the title is the title of an issue opened by the analyzer.
+func IsAnalyzerIssue(title string) bool {
+	return title == oldIssueTitle || title == issueTitle
 }
 
-func safeIssueBody(err error) string {
+// safeLog returns the message of the error without the values of the sensitive environment variables.
+func safeLog(err error) string {
 	msgBody := err.Error()
 
 	var repKeys []string
@@ -723,7 +1162,6 @@ func safeIssueBody(err error) string {
 	}
 
 	replacer := strings.NewReplacer(replacements...)
-	msgBody = replacer.Replace(msgBody)
 
-	return fmt.Sprintf(issueContent, msgBody)
+	return replacer.Replace(msgBody)
 }
@@ -23,6 +23,9 @@ type mockPluginClient struct {
 	create    func(p plugin.Plugin) error
 	update    func(p plugin.Plugin) error
 	getByName func(string) (*plugin.Plugin, error)
+	list      func() ([]plugin.Plugin, error)
+	delete    func(id string) error
+	deprecate func(p plugin.Plugin) error
 }
 
 func (f *mockPluginClient) Create(_ context.Context, p plugin.Plugin) error {
@@ -50,6 +53,31 @@ func (f *mockPluginClient) GetByName(_ context.Context, name string) (*plugin.Pl
 	return nil, nil
 }
 
+func (f *mockPluginClient) List(_ context.Context) ([]plugin.Plugin, error) {
+	if f.list != nil {
+		return f.list()
+	}
+	return nil, nil
+}
+
+func (f *mockPluginClient) Delete(_ context.Context, id string) error {
+	log.Info().Str("id", id).Msg("Delete plugin")
+
+	if f.delete != nil {
+		return f.delete(id)
+	}
+	return nil
+}
+
+func (f *mockPluginClient) Deprecate(_ context.Context, p plugin.Plugin) error {
+	log.Info().Str("module_name", p.Name).Msg("Deprecate plugin")
+
+	if f.deprecate != nil {
+		return f.deprecate(p)
+	}
+	return nil
+}
+
 func Test_loadManifestContent(t *testing.T) {
 	testCases := []struct {
 		desc     string
@@ -92,6 +120,22 @@ func Test_loadManifestContent(t *testing.T) {
 						int64(3),
 					},
 				},
+				rawTestData: map[string]interface{}{
+					"Headers": map[string]interface{}{
+						"Foo": "Bar",
+					},
+					"trustIP": []interface{}{
+						"10.0.0.0/8",
+						"172.0.0.0/8",
+						"192.0.0.0/8",
+					},
+					"allowedGroups": []interface{}{
+						"ou=mathematicians,dc=example,dc=com",
+						"ou=foo,ou=scientists,dc=example,dc=com",
+					},
+					"valuesFloat": []interface{}{1, 2.01, 3.01},
+					"valuesInt":   []interface{}{1, 2, 3},
+				},
 			},
 		},
 		{
@@ -109,6 +153,9 @@ func Test_loadManifestContent(t *testing.T) {
 				TestData: map[string]interface{}{
 					"Foo": "Bar",
 				},
+				rawTestData: map[string]interface{}{
+					"Foo": "Bar",
+				},
 			},
 		},
 	}
@@ -137,7 +184,8 @@ func Test_loadManifestContent(t *testing.T) {
 func TestScrapper_store(t *testing.T) {
 	testCases := []struct {
 		desc     string
-		pgClient pluginClient
+		pgClient PluginClient
+		expected string
 	}{
 		{
 			desc: "create",
@@ -146,14 +194,34 @@ func TestScrapper_store(t *testing.T) {
 					return nil, &plugin.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
 				},
 			},
+			expected: OutcomeCreated,
 		},
 		{
 			desc: "update",
 			pgClient: &mockPluginClient{
 				getByName: func(_ string) (*plugin.Plugin, error) {
-					return &plugin.Plugin{ID: "aaaa", Name: "test"}, nil
+					return &plugin.Plugin{ID: "aaaa", Name: "test", LatestVersion: "v0.1.0"}, nil
 				},
 			},
+			expected: OutcomeUpdated,
+		},
+		{
+			desc: "unchanged",
+			pgClient: &mockPluginClient{
+				getByName: func(_ string) (*plugin.Plugin, error) {
+					return &plugin.Plugin{ID: "aaaa", Name: "test", LatestVersion: "v0.2.0"}, nil
+				},
+			},
+			expected: OutcomeUnchanged,
+		},
+		{
+			desc: "restore deprecated",
+			pgClient: &mockPluginClient{
+				getByName: func(_ string) (*plugin.Plugin, error) {
+					return &plugin.Plugin{ID: "aaaa", Name: "test", LatestVersion: "v0.2.0", Deprecated: true}, nil
+				},
+			},
+			expected: OutcomeUpdated,
 		},
 	}
 
@@ -163,10 +231,62 @@ func TestScrapper_store(t *testing.T) {
 
 			scrapper := NewScrapper(nil, nil, test.pgClient, true, nil, nil, nil)
 
-			data := &plugin.Plugin{Name: "test"}
-			err := scrapper.store(context.Background(), data)
+			data := &plugin.Plugin{Name: "test", LatestVersion: "v0.2.0"}
+			outcome, err := scrapper.store(context.Background(), &github.Repository{}, data)
 
 			require.NoError(t, err)
+			assert.Equal(t, test.expected, outcome)
+		})
+	}
+}
+
+func Test_isUnchanged(t *testing.T) {
+	repository := &github.Repository{
+		Name:            github.String("plugintest"),
+		Owner:           &github.User{Login: github.String("traefik")},
+		StargazersCount: github.Int(10),
+	}
+
+	testCases := []struct {
+		desc     string
+		prev     *plugin.Plugin
+		expected bool
+	}{
+		{
+			desc:     "unchanged",
+			prev:     &plugin.Plugin{Author: "traefik", RepoName: "plugintest", LatestVersion: "v0.2.0", Stars: 10},
+			expected: true,
+		},
+		{
+			desc: "not in the catalog",
+		},
+		{
+			desc: "new version",
+			prev: &plugin.Plugin{Author: "traefik", RepoName: "plugintest", LatestVersion: "v0.1.0", Stars: 10},
+		},
+		{
+			desc: "new stars",
+			prev: &plugin.Plugin{Author: "traefik", RepoName: "plugintest", LatestVersion: "v0.2.0", Stars: 9},
+		},
+		{
+			desc: "deprecated",
+			prev: &plugin.Plugin{Author: "traefik", RepoName: "plugintest", LatestVersion: "v0.2.0", Stars: 10, Deprecated: true},
+		},
+		{
+			desc: "renamed",
+			prev: &plugin.Plugin{Author: "traefik", RepoName: "oldname", LatestVersion: "v0.2.0", Stars: 10},
+		},
+		{
+			desc: "transferred",
+			prev: &plugin.Plugin{Author: "ldez", RepoName: "plugintest", LatestVersion: "v0.2.0", Stars: 10},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, isUnchanged(test.prev, repository, "v0.2.0"))
 		})
 	}
 }
@@ -182,11 +302,13 @@ func Test_createMiddlewareSnippets(t *testing.T) {
 		},
 	}
 
-	snippets, err := createMiddlewareSnippets(repository, testData)
+	snippets, warnings, err := createMiddlewareSnippets(repository, testData)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	assert.Empty(t, warnings)
+
 	expected := map[string]interface{}{
 		"toml": `
 [http]
@@ -220,6 +342,32 @@ spec:
         plugintest:
             Headers:
                 Foo: Bar
+`,
+		"gateway": `apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+    name: my-route
+    namespace: my-namespace
+spec:
+    parentRefs:
+        - name: my-gateway
+    rules:
+        - backendRefs:
+            - name: my-service
+              port: 80
+          filters:
+            - extensionRef:
+                group: traefik.io
+                kind: Middleware
+                name: my-plugintest
+              type: ExtensionRef
+`,
+		"docker": `labels:
+    traefik.http.middlewares.my-plugintest.plugin.plugintest.Headers.Foo: Bar
+`,
+		"tags": `tags = [
+  "traefik.http.middlewares.my-plugintest.plugin.plugintest.Headers.Foo=Bar",
+]
 `,
 	}
 
@@ -235,11 +383,13 @@ func Test_createProviderSnippets(t *testing.T) {
 		"foo": "Bar",
 	}
 
-	snippets, err := createProviderSnippets(repository, testData)
+	snippets, warnings, err := createProviderSnippets(repository, testData)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	assert.Empty(t, warnings)
+
 	expected := map[string]interface{}{
 		"toml": `
 [providers]
@@ -254,11 +404,39 @@ func Test_createProviderSnippets(t *testing.T) {
         plugintest:
             foo: Bar
 `,
+		"cli": "--providers.plugin.plugintest.foo=Bar\n",
 	}
 
 	assert.Equal(t, expected, snippets)
 }
 
+func Test_createSnippets_invalidKey(t *testing.T) {
+	repository := &github.Repository{
+		Name: github.String("plugintest"),
+	}
+
+	testData := map[string]interface{}{
+		"Headers": map[string]interface{}{
+			"X.Foo": "bar",
+		},
+	}
+
+	snippets, warnings, err := createSnippets(repository, Manifest{Type: typeMiddleware, TestData: testData})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Warning{{Code: WarningSnippetOmitted, Message: `the docker and tags snippets are omitted: the key "X.Foo" of traefik.http.middlewares.my-plugintest.plugin.plugintest.Headers cannot be used in a label`}}, warnings)
+	assert.NotContains(t, snippets, "docker")
+	assert.NotContains(t, snippets, "tags")
+	assert.Contains(t, snippets, "k8s")
+
+	snippets, warnings, err = createSnippets(repository, Manifest{Type: typeProvider, TestData: testData})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Warning{{Code: WarningSnippetOmitted, Message: `the cli snippet is omitted: the key "X.Foo" of providers.plugin.plugintest.Headers cannot be used in a label`}}, warnings)
+	assert.NotContains(t, snippets, "cli")
+	assert.Contains(t, snippets, "yaml")
+}
+
 func Test_parseImageURL(t *testing.T) {
 	repo := &github.Repository{
 		Owner: &github.User{
@@ -316,6 +494,48 @@ func Test_parseImageURL(t *testing.T) {
 	}
 }
 
+func Test_parseImageURL_enterprise(t *testing.T) {
+	repo := &github.Repository{
+		Owner: &github.User{
+			Login: github.String("traefik"),
+		},
+		Name:    github.String("traefik"),
+		HTMLURL: github.String("https://github.example.com/traefik/traefik"),
+	}
+
+	testCases := []struct {
+		desc     string
+		imgPath  string
+		expected string
+	}{
+		{
+			desc:     "full URL with /raw",
+			imgPath:  "https://github.example.com/traefik/traefik/raw/v2.0.0/docs/content/assets/img/traefik.logo.png",
+			expected: "https://github.example.com/traefik/traefik/raw/v2.0.0/docs/content/assets/img/traefik.logo.png",
+		},
+		{
+			desc:     "full URL with raw.githubusercontent.com",
+			imgPath:  "https://raw.githubusercontent.com/traefik/traefik/master/docs/content/assets/img/traefik.logo.png",
+			expected: "",
+		},
+		{
+			desc:     "relative path",
+			imgPath:  "./docs/content/assets/img/traefik.logo.png",
+			expected: "https://github.example.com/traefik/traefik/raw/v2.0.0/docs/content/assets/img/traefik.logo.png",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			imgURL := parseImageURL(repo, "v2.0.0", test.imgPath)
+
+			assert.Equal(t, test.expected, imgURL)
+		})
+	}
+}
+
 func TestScrapper_process(t *testing.T) {
 	t.Skip("for debug purpose only")
 
@@ -338,7 +558,7 @@ func TestScrapper_process(t *testing.T) {
 	repository, _, err := ghClient.Repositories.Get(ctx, owner, repo)
 	require.NoError(t, err)
 
-	p, err := scrapper.process(ctx, repository)
+	p, _, err := scrapper.process(ctx, repository)
 	require.NoError(t, err)
 
 	assert.NotNil(t, p)
@@ -363,25 +583,64 @@ func TestScrapper_process_all(t *testing.T) {
 	reposWithExistingIssue, err := scrapper.searchReposWithExistingIssue(ctx)
 	require.NoError(t, err)
 
-	repositories, err := scrapper.search(ctx)
+	repositories, _, err := scrapper.search(ctx)
 	require.NoError(t, err)
 
 	for _, repository := range repositories {
 		logger := log.With().Str("repo_name", repository.GetFullName()).Logger()
 
-		if scrapper.isSkipped(logger.WithContext(ctx), reposWithExistingIssue, repository) {
+		if scrapper.skipReason(logger.WithContext(ctx), reposWithExistingIssue, repository) != "" {
 			continue
 		}
 
 		t.Log(repository.GetFullName())
-		_, err := scrapper.process(ctx, repository)
+		_, _, err := scrapper.process(ctx, repository)
 		if err != nil {
 			t.Logf("%s: %v", repository.GetFullName(), err)
 		}
 	}
 }
 
-func Test_safeIssueBody(t *testing.T) {
+func TestScrapper_skipReason_blocklist(t *testing.T) {
+	scrapper := NewScrapper(nil, nil, nil, false, nil, nil, nil)
+
+	for key := range scrapper.blacklist {
+		assert.Equal(t, repositoryKey(key), key)
+	}
+
+	testCases := []struct {
+		desc     string
+		fullName string
+		expected string
+	}{
+		{
+			desc:     "mixed-case full name",
+			fullName: "FinalCAD/TraefikGrpcWebPlugin",
+			expected: SkipReasonBlocklisted,
+		},
+		{
+			desc:     "lowercase full name",
+			fullName: "gdgvit/securum-exire",
+			expected: SkipReasonBlocklisted,
+		},
+		{
+			desc:     "not blocked",
+			fullName: "traefik/plugindemo",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			repository := &github.Repository{FullName: github.String(test.fullName)}
+
+			assert.Equal(t, test.expected, scrapper.skipReason(context.Background(), nil, repository))
+		})
+	}
+}
+
+func Test_safeLog(t *testing.T) {
 	t.Setenv("FEATURE_SERVICE_PORT", "tcp://172.20.236.87:80")
 	t.Setenv("FEATURE_SERVICE_PORT_80_TCP", "tcp://172.20.236.87:80")
 	t.Setenv("FEATURE_SERVICE_PORT_80_TCP_ADDR", "172.20.236.87")
@@ -512,9 +771,9 @@ func Test_safeIssueBody(t *testing.T) {
 
 	err := errors.New(`failed to run the plugin with Yaegi: failed to create a new plugin instance: failed to open database: open /root/go/src/github.com/nscuro/traefik-plugin-geoblock/IP2LOCATION-LITE-DB1.IPV6.BIN: no such file or directory (cwd: /, gopath: /root/go, env: []string{"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin", "HOSTNAME=piceus-job-1634142000-hltrs", "PICEUS_PRIVATE_MODE=true", "TRACING_USERNAME=jaeger", "TRACING_PASSWORD=c487989b32abb6fa024c70443e6db7205ef4d5e62c8d55e9a89a0eea9c72f0de", "TRACING_PROBABILITY=0", "GITHUB_TOKEN=d29e33c33d871a2c7300b14069b14643b54b5aeeadfc1347de15404fcb3a3cd2", "SERVICES_ACCESS_TOKEN=7138b22877a8e9f9caf95528dbe82ff25fb2e836ce459a2b99b53f2aa336aca9", "PLUGIN_URL=http://plugin-service/internal/", "SUBSCRIPTION_SERVICE_SERVICE_PORT_SUBSCRIPTION_SERVICE=80", "ORGANIZATION_SERVICE_PORT_80_TCP_PROTO=tcp", "SECURITY_ISSUES_SERVICE_PORT_80_TCP_ADDR=172.20.112.31", "KUBERNETES_PORT_443_TCP_ADDR=172.20.0.1", "KUBERNETES_SERVICE_HOST=172.20.0.1", "KUBERNETES_SERVICE_PORT_HTTPS=443", "THANOS_COMPACTOR_PORT_10902_TCP_PORT=10902", "THANOS_COMPACTOR_PORT_10902_TCP_ADDR=172.20.133.173", "USER_SERVICE_PORT=tcp://172.20.238.163:80", "FEATURE_SERVICE_SERVICE_PORT=80", "SUBSCRIPTION_SERVICE_PORT_80_TCP_PORT=80", "ORGANIZATION_SERVICE_PORT=tcp://172.20.250.189:80", "SECURITY_ISSUES_SERVICE_PORT=tcp://172.20.112.31:80", "MONITORING_SERVICE_PORT_80_TCP_ADDR=172.20.94.195", "PLATFORM_WEBAPP_PORT_80_TCP=tcp://172.20.6.189:80", "MULTI_CLUSTER_SERVICE_SERVICE_PORT_MULTI_CLUSTER_SERVICE=80", "SUBSCRIPTION_SERVICE_PORT_80_TCP_PROTO=tcp", "PLATFORM_WEBAPP_PORT_80_TCP_ADDR=172.20.6.189", "MONITORING_SERVICE_PORT_80_TCP_PORT=80", "SUBSCRIPTION_SERVICE_PORT=tcp://172.20.32.181:80", "PLUGIN_SERVICE_PORT=tcp://172.20.37.203:80", "TOKEN_SERVICE_PORT_80_TCP_PROTO=tcp", "PLUGIN_SERVICE_PORT_80_TCP_PROTO=tcp", "ORGANIZATION_SERVICE_PORT_80_TCP_ADDR=172.20.250.189", "THANOS_COMPACTOR_SERVICE_HOST=172.20.133.173", "SUBSCRIPTION_SERVICE_SERVICE_PORT=80", "FEATURE_SERVICE_PORT_80_TCP_PORT=80", "SECURITY_ISSUES_SERVICE_SERVICE_PORT=80", "SECURITY_ISSUES_SERVICE_PORT_80_TCP_PROTO=tcp", "TOKEN_SERVICE_SERVICE_PORT=80", "INSTANCE_INFO_SERVICE_SERVICE_PORT=80", "PLUGIN_SERVICE_PORT_80_TCP_PORT=80", "KUBERNETES_PORT=tcp://172.20.0.1:443", "METRIC_SERVICE_SERVICE_HOST=172.20.75.223", "KUBERNETES_PORT_443_TCP=tcp://172.20.0.1:443", "METRIC_SERVICE_PORT=tcp://172.20.75.223:80", "PLUGIN_SERVICE_PORT_80_TCP=tcp://172.20.37.203:80", "ORGANIZATION_SERVICE_SERVICE_PORT=80", "MULTI_CLUSTER_SERVICE_PORT_80_TCP_PROTO=tcp", "KUBERNETES_SERVICE_PORT=443", "USER_SERVICE_SERVICE_PORT=80", "INSTANCE_INFO_SERVICE_SERVICE_HOST=172.20.17.141", "PLUGIN_SERVICE_PORT_80_TCP_ADDR=172.20.37.203", "SECURITY_ISSUES_SERVICE_SERVICE_HOST=172.20.112.31", "MULTI_CLUSTER_SERVICE_PORT=tcp://172.20.4.179:80", "MONITORING_SERVICE_PORT_80_TCP_PROTO=tcp", "USER_SERVICE_PORT_80_TCP_ADDR=172.20.238.163", "INSTANCE_INFO_SERVICE_SERVICE_PORT_INSTANCE_INFO_SERVICE=80", "SUBSCRIPTION_SERVICE_SERVICE_HOST=172.20.32.181", "MULTI_CLUSTER_SERVICE_PORT_80_TCP_ADDR=172.20.4.179", "MONITORING_SERVICE_PORT_80_TCP=tcp://172.20.94.195:80", "METRIC_SERVICE_SERVICE_PORT=80", "INSTANCE_INFO_SERVICE_PORT_80_TCP_PORT=80", "FEATURE_SERVICE_PORT_80_TCP_PROTO=tcp", "PLATFORM_WEBAPP_SERVICE_PORT=80", "TOKEN_SERVICE_PORT_80_TCP=tcp://172.20.180.224:80", "MULTI_CLUSTER_SERVICE_PORT_80_TCP=tcp://172.20.4.179:80", "KUBERNETES_PORT_443_TCP_PORT=443", "THANOS_COMPACTOR_PORT_10902_TCP_PROTO=tcp", "METRIC_SERVICE_PORT_80_TCP=tcp://172.20.75.223:80", "ORGANIZATION_SERVICE_PORT_80_TCP=tcp://172.20.250.189:80", "TOKEN_SERVICE_SERVICE_HOST=172.20.180.224", "MONITORING_SERVICE_SERVICE_PORT_MONITORING_SERVICE=80", "FEATURE_SERVICE_PORT_80_TCP=tcp://172.20.236.87:80", "INSTANCE_INFO_SERVICE_PORT_80_TCP_PROTO=tcp", "FEATURE_SERVICE_PORT_80_TCP_ADDR=172.20.236.87", "THANOS_COMPACTOR_PORT=tcp://172.20.133.173:10902", "USER_SERVICE_SERVICE_HOST=172.20.238.163", "ORGANIZATION_SERVICE_SERVICE_HOST=172.20.250.189", "TOKEN_SERVICE_SERVICE_PORT_TOKEN_SERVICE=80", "MULTI_CLUSTER_SERVICE_SERVICE_PORT=80", "MONITORING_SERVICE_PORT=tcp://172.20.94.195:80", "NOTIFICATION_SERVICE_PORT_80_TCP_PROTO=tcp", "MULTI_CLUSTER_SERVICE_SERVICE_HOST=172.20.4.179", "USER_SERVICE_PORT_80_TCP_PROTO=tcp", "PLATFORM_WEBAPP_PORT=tcp://172.20.6.189:80", "ORGANIZATION_SERVICE_PORT_80_TCP_PORT=80", "SECURITY_ISSUES_SERVICE_PORT_80_TCP=tcp://172.20.112.31:80", "NOTIFICATION_SERVICE_PORT=tcp://172.20.246.182:80", "KUBERNETES_PORT_443_TCP_PROTO=tcp", "FEATURE_SERVICE_SERVICE_PORT_FEATURE_SERVICE=80", "USER_SERVICE_PORT_80_TCP=tcp://172.20.238.163:80", "SUBSCRIPTION_SERVICE_PORT_80_TCP=tcp://172.20.32.181:80", "PLATFORM_WEBAPP_PORT_80_TCP_PORT=80", "TOKEN_SERVICE_PORT_80_TCP_PORT=80", "NOTIFICATION_SERVICE_PORT_80_TCP=tcp://172.20.246.182:80", "SUBSCRIPTION_SERVICE_PORT_80_TCP_ADDR=172.20.32.181", "NOTIFICATION_SERVICE_SERVICE_PORT=80", "MONITORING_SERVICE_SERVICE_HOST=172.20.94.195", "USER_SERVICE_SERVICE_PORT_USER_SERVICE=80", "THANOS_COMPACTOR_PORT_10902_TCP=tcp://172.20.133.173:10902", "INSTANCE_INFO_SERVICE_PORT_80_TCP_ADDR=172.20.17.141", "FEATURE_SERVICE_PORT=tcp://172.20.236.87:80", "PLUGIN_SERVICE_SERVICE_HOST=172.20.37.203", "NOTIFICATION_SERVICE_PORT_80_TCP_PORT=80", "MONITORING_SERVICE_SERVICE_PORT=80", "METRIC_SERVICE_PORT_80_TCP_PROTO=tcp", "THANOS_COMPACTOR_SERVICE_PORT=10902", "INSTANCE_INFO_SERVICE_PORT_80_TCP=tcp://172.20.17.141:80", "PLATFORM_WEBAPP_PORT_80_TCP_PROTO=tcp", "ORGANIZATION_SERVICE_SERVICE_PORT_ORGANIZATION_SERVICE=80", "TOKEN_SERVICE_PORT=tcp://172.20.180.224:80", "MULTI_CLUSTER_SERVICE_PORT_80_TCP_PORT=80", "PLATFORM_WEBAPP_SERVICE_PORT_PLATFORM_WEBAPP=80", "INSTANCE_INFO_SERVICE_PORT=tcp://172.20.17.141:80", "PLATFORM_WEBAPP_SERVICE_HOST=172.20.6.189", "USER_SERVICE_PORT_80_TCP_PORT=80", "FEATURE_SERVICE_SERVICE_HOST=172.20.236.87", "NOTIFICATION_SERVICE_SERVICE_PORT_NOTIFICATION_SERVICE=80", "NOTIFICATION_SERVICE_PORT_80_TCP_ADDR=172.20.246.182", "METRIC_SERVICE_PORT_80_TCP_ADDR=172.20.75.223", "PLUGIN_SERVICE_SERVICE_PORT=80", "PLUGIN_SERVICE_SERVICE_PORT_PLUGIN_SERVICE=80", "TOKEN_SERVICE_PORT_80_TCP_ADDR=172.20.180.224", "METRIC_SERVICE_SERVICE_PORT_METRIC_SERVICE=80", "SECURITY_ISSUES_SERVICE_SERVICE_PORT_SECURITY_ISSUES_SERVICE=80", "SECURITY_ISSUES_SERVICE_PORT_80_TCP_PORT=80", "NOTIFICATION_SERVICE_SERVICE_HOST=172.20.246.182", "METRIC_SERVICE_PORT_80_TCP_PORT=80", "HOME=/root"})`)
 
-	body := safeIssueBody(err)
+	body := safeLog(err)
 
-	expected := "The plugin was not imported into Traefik Plugin Catalog.\n\nCause:\n```\nfailed to run the plugin with Yaegi: failed to create a new plugin instance: failed to open database: open /root/go/src/github.com/nscuro/traefik-plugin-geoblock/IP2LOCATION-LITE-DB1.IPV6.BIN: no such file or directory (cwd: /, gopath: /root/go, env: []string{\"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin\", \"HOSTNAME=piceus-job-1634142000-hltrs\", \"PICEUS_PRIVATE_MODE=true\", \"xxx=xxx\", \"xxx=xxx\", \"TRACING_PROBABILITY=0\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=10902\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=10902\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"HOME=/root\"})\n```\nTraefik Plugin Analyzer will restart when you will close this issue.\n\nIf you believe there is a problem with the Analyzer or this issue is the result of a false positive, please fill an issue on [piceus](https://github.com/traefik/piceus) repository.\n"
+	expected := "failed to run the plugin with Yaegi: failed to create a new plugin instance: failed to open database: open /root/go/src/github.com/nscuro/traefik-plugin-geoblock/IP2LOCATION-LITE-DB1.IPV6.BIN: no such file or directory (cwd: /, gopath: /root/go, env: []string{\"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin\", \"HOSTNAME=piceus-job-1634142000-hltrs\", \"PICEUS_PRIVATE_MODE=true\", \"xxx=xxx\", \"xxx=xxx\", \"TRACING_PROBABILITY=0\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=10902\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=443\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=10902\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"xxx=xxx\", \"HOME=/root\"})"
 
 	assert.Equal(t, expected, body)
 }
@@ -0,0 +1,215 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// labelRoot the root of the Traefik labels and tags.
+const labelRoot = "traefik"
+
+// createInstallSnippets creates the static configuration snippets to install the plugin:
+// from the plugin catalog (`experimental.plugins`), and from the local sources (`experimental.localPlugins`).
+func createInstallSnippets(repository *github.Repository, moduleName, version string) (map[string]interface{}, error) {
+	key := repository.GetName()
+
+	plugins := map[string]interface{}{
+		"experimental": map[string]interface{}{
+			"plugins": map[string]interface{}{
+				key: map[string]interface{}{
+					"moduleName": moduleName,
+					"version":    version,
+				},
+			},
+		},
+	}
+
+	yamlSnip, err := yaml.Marshal(plugins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+	}
+
+	tomlSnip, err := toml.Marshal(plugins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall (TOML): %w", err)
+	}
+
+	// The local plugins are loaded from the plugins-local/src/<moduleName> directory.
+	localPlugins := map[string]interface{}{
+		"experimental": map[string]interface{}{
+			"localPlugins": map[string]interface{}{
+				key: map[string]interface{}{
+					"moduleName": moduleName,
+				},
+			},
+		},
+	}
+
+	localYAMLSnip, err := yaml.Marshal(localPlugins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall (YAML): %w", err)
+	}
+
+	localTOMLSnip, err := toml.Marshal(localPlugins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshall (TOML): %w", err)
+	}
+
+	return map[string]interface{}{
+		"toml": string(tomlSnip),
+		"yaml": string(yamlSnip),
+		"cli": cliFlags(map[string]string{
+			"experimental.plugins." + key + ".moduleName": moduleName,
+			"experimental.plugins." + key + ".version":    version,
+		}),
+		// The values of the Helm chart use the same structure as the static configuration.
+		"helm": string(yamlSnip),
+		"local": map[string]interface{}{
+			"toml": string(localTOMLSnip),
+			"yaml": string(localYAMLSnip),
+			"cli": cliFlags(map[string]string{
+				"experimental.localPlugins." + key + ".moduleName": moduleName,
+			}),
+		},
+	}, nil
+}
+
+// labelKeyError a key of the test data which cannot be flattened: the label parser uses dots, brackets and equal signs as separators.
+type labelKeyError struct {
+	key  string
+	name string
+}
+
+func (e labelKeyError) Error() string {
+	return fmt.Sprintf("the key %q of %s cannot be used in a label", e.key, e.name)
+}
+
+// flattenTestData flattens the test data to the labels expected by the Traefik label parser:
+// the keys of the maps are separated by dots, the items of the slices are indexed (ex: `prefix.list[0]`).
+// The empty maps and slices, and the nil values, cannot be represented and are skipped.
+func flattenTestData(prefix string, testData map[string]interface{}) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	err := flattenValue(labels, prefix, reflect.ValueOf(testData))
+	if err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+func flattenValue(labels map[string]string, name string, value reflect.Value) error {
+	if value.Kind() == reflect.Interface || value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+
+		return flattenValue(labels, name, value.Elem())
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		for _, key := range value.MapKeys() {
+			k := fmt.Sprint(key.Interface())
+			if k == "" || strings.ContainsAny(k, ".[]=") {
+				return labelKeyError{key: k, name: name}
+			}
+
+			err := flattenValue(labels, name+"."+k, value.MapIndex(key))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			err := flattenValue(labels, fmt.Sprintf("%s[%d]", name, i), value.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+
+	case reflect.Invalid:
+		// nil value.
+
+	default:
+		labels[name] = formatScalar(value)
+	}
+
+	return nil
+}
+
+// formatScalar formats a scalar value like in a label.
+func formatScalar(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// sortedLabels returns the labels as `key=value`, sorted by key.
+func sortedLabels(labels map[string]string) []string {
+	lines := make([]string, 0, len(labels))
+	for _, k := range sortedKeys(labels) {
+		lines = append(lines, k+"="+labels[k])
+	}
+
+	return lines
+}
+
+// cliFlags returns the labels as CLI flags.
+func cliFlags(labels map[string]string) string {
+	var b strings.Builder
+
+	for _, label := range sortedLabels(labels) {
+		key, value, _ := strings.Cut(label, "=")
+		b.WriteString("--" + key + "=" + shellQuote(value) + "\n")
+	}
+
+	return b.String()
+}
+
+// hclTags returns the labels as a list of tags (Nomad job, Consul service definition).
+func hclTags(labels map[string]string) string {
+	var b strings.Builder
+
+	b.WriteString("tags = [\n")
+
+	for _, label := range sortedLabels(labels) {
+		// The HCL template sequences must be escaped.
+		label = strings.NewReplacer("${", "$${", "%{", "%%{").Replace(label)
+		b.WriteString("  " + strconv.Quote(label) + ",\n")
+	}
+
+	b.WriteString("]\n")
+
+	return b.String()
+}
+
+// shellQuote quotes a value for a POSIX shell, if needed.
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, isShellSpecial) == -1 {
+		return value
+	}
+
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func isShellSpecial(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return false
+	case strings.ContainsRune("-_.,:/@%+=", r):
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,166 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_flattenTestData(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		testData map[string]interface{}
+		expected map[string]string
+	}{
+		{
+			desc:     "empty",
+			testData: map[string]interface{}{},
+			expected: map[string]string{},
+		},
+		{
+			desc: "nested maps",
+			testData: map[string]interface{}{
+				"Headers": map[string]interface{}{
+					"Foo": "Bar",
+					"Sub": map[string]interface{}{"Key": "value"},
+				},
+			},
+			expected: map[string]string{
+				"traefik.plugin.Headers.Foo":     "Bar",
+				"traefik.plugin.Headers.Sub.Key": "value",
+			},
+		},
+		{
+			desc: "slices",
+			testData: map[string]interface{}{
+				"Values": []interface{}{"a", "b"},
+				"Items": []interface{}{
+					map[string]interface{}{"Name": "foo"},
+					map[string]interface{}{"Name": "bar"},
+				},
+				"Typed": []string{"c"},
+			},
+			expected: map[string]string{
+				"traefik.plugin.Values[0]":     "a",
+				"traefik.plugin.Values[1]":     "b",
+				"traefik.plugin.Items[0].Name": "foo",
+				"traefik.plugin.Items[1].Name": "bar",
+				"traefik.plugin.Typed[0]":      "c",
+			},
+		},
+		{
+			desc: "scalars",
+			testData: map[string]interface{}{
+				"Bool":  true,
+				"Int":   42,
+				"Float": 1000000.5,
+				"Nil":   nil,
+				"Empty": map[string]interface{}{},
+			},
+			expected: map[string]string{
+				"traefik.plugin.Bool":  "true",
+				"traefik.plugin.Int":   "42",
+				"traefik.plugin.Float": "1000000.5",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			labels, err := flattenTestData("traefik.plugin", test.testData)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, labels)
+		})
+	}
+}
+
+func Test_flattenTestData_invalidKey(t *testing.T) {
+	_, err := flattenTestData("traefik.plugin", map[string]interface{}{"foo.bar": "value"})
+	require.ErrorAs(t, err, &labelKeyError{})
+}
+
+func Test_cliFlags(t *testing.T) {
+	labels := map[string]string{
+		"providers.plugin.test.b": "it's a value",
+		"providers.plugin.test.a": "foo,bar",
+		"providers.plugin.test.c": "",
+	}
+
+	expected := `--providers.plugin.test.a=foo,bar
+--providers.plugin.test.b='it'\''s a value'
+--providers.plugin.test.c=''
+`
+
+	assert.Equal(t, expected, cliFlags(labels))
+}
+
+func Test_hclTags(t *testing.T) {
+	labels := map[string]string{
+		"traefik.plugin.b": `${var} "quoted"`,
+		"traefik.plugin.a": "foo",
+	}
+
+	expected := `tags = [
+  "traefik.plugin.a=foo",
+  "traefik.plugin.b=$${var} \"quoted\"",
+]
+`
+
+	assert.Equal(t, expected, hclTags(labels))
+}
+
+func Test_createInstallSnippets(t *testing.T) {
+	repository := &github.Repository{
+		Name: github.String("plugintest"),
+	}
+
+	snippets, err := createInstallSnippets(repository, "github.com/traefik/plugintest", "v0.1.0")
+	require.NoError(t, err)
+
+	staticYAML := `experimental:
+    plugins:
+        plugintest:
+            moduleName: github.com/traefik/plugintest
+            version: v0.1.0
+`
+
+	expected := map[string]interface{}{
+		"toml": `
+[experimental]
+
+  [experimental.plugins]
+
+    [experimental.plugins.plugintest]
+      moduleName = "github.com/traefik/plugintest"
+      version = "v0.1.0"
+`,
+		"yaml": staticYAML,
+		"cli": `--experimental.plugins.plugintest.moduleName=github.com/traefik/plugintest
+--experimental.plugins.plugintest.version=v0.1.0
+`,
+		"helm": staticYAML,
+		"local": map[string]interface{}{
+			"toml": `
+[experimental]
+
+  [experimental.localPlugins]
+
+    [experimental.localPlugins.plugintest]
+      moduleName = "github.com/traefik/plugintest"
+`,
+			"yaml": `experimental:
+    localPlugins:
+        plugintest:
+            moduleName: github.com/traefik/plugintest
+`,
+			"cli": "--experimental.localPlugins.plugintest.moduleName=github.com/traefik/plugintest\n",
+		},
+	}
+
+	assert.Equal(t, expected, snippets)
+}
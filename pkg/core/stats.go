@@ -0,0 +1,49 @@
+package core
+
+// Outcomes of the analysis of a repository.
+const (
+	// OutcomeSkipped the repository is not analyzed (see the skip reasons).
+	OutcomeSkipped = "skipped"
+	// OutcomeFailed the analysis failed, an issue is opened.
+	OutcomeFailed = "failed"
+	// OutcomeError the analysis failed because of an infrastructure error (ex: GitHub API unavailable).
+	OutcomeError = "error"
+	// OutcomeCreated the plugin is added to the catalog.
+	OutcomeCreated = "created"
+	// OutcomeUpdated the plugin is updated.
+	OutcomeUpdated = "updated"
+	// OutcomeUnchanged the plugin is already up to date (or the dry run mode is enabled).
+	OutcomeUnchanged = "unchanged"
+	// OutcomeReconciled the plugin is deprecated or deleted, according to the reconcile mode.
+	OutcomeReconciled = "reconciled"
+)
+
+// Stats the statistics of a run.
+type Stats struct {
+	Repositories int `json:"repositories"`
+	Skipped      int `json:"skipped"`
+	Failed       int `json:"failed"`
+	Errors       int `json:"errors"`
+	Created      int `json:"created"`
+	Updated      int `json:"updated"`
+	Unchanged    int `json:"unchanged"`
+}
+
+func (s *Stats) add(outcome string) {
+	s.Repositories++
+
+	switch outcome {
+	case OutcomeSkipped:
+		s.Skipped++
+	case OutcomeFailed:
+		s.Failed++
+	case OutcomeError:
+		s.Errors++
+	case OutcomeCreated:
+		s.Created++
+	case OutcomeUpdated:
+		s.Updated++
+	case OutcomeUnchanged:
+		s.Unchanged++
+	}
+}
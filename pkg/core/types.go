@@ -26,4 +26,7 @@ type Manifest struct {
 	BannerPath    string                 `json:"bannerPath,omitempty" toml:"bannerPath,omitempty" yaml:"bannerPath,omitempty"`
 	UseUnsafe     bool                   `json:"useUnsafe,omitempty" toml:"useUnsafe,omitempty" yaml:"useUnsafe,omitempty"`
 	TestData      map[string]interface{} `json:"testData,omitempty" toml:"testData,omitempty" yaml:"testData,omitempty"`
+
+	// rawTestData the test data with the YAML scalar types, before its decoding like the Traefik file provider.
+	rawTestData map[string]interface{}
 }
@@ -24,11 +24,11 @@ const wasmFile = "plugin.wasm"
 const wasmCheckTimeout = 60 * time.Second
 
 func (s *Scrapper) verifyWASMPlugin(ctx context.Context, repository *github.Repository, latestVersion string, manifest Manifest) (string, []string, error) {
-	pluginName := path.Join("github.com", repository.GetFullName())
+	pluginName := path.Join(s.moduleHost, repository.GetFullName())
 
 	// skip already existing plugin
 	prev, err := s.pg.GetByName(ctx, pluginName)
-	if err == nil && prev != nil && prev.LatestVersion == latestVersion && prev.Stars == repository.GetStargazersCount() {
+	if err == nil && isUnchanged(prev, repository, latestVersion) {
 		return "", nil, nil
 	}
 
@@ -49,7 +49,7 @@ func (s *Scrapper) verifyWASMPlugin(ctx context.Context, repository *github.Repo
 func (s *Scrapper) verifyRelease(ctx context.Context, repository *github.Repository, manifest Manifest) error {
 	release, _, err := s.gh.Repositories.GetLatestRelease(ctx, repository.GetOwner().GetLogin(), repository.GetName())
 	if err != nil {
-		return fmt.Errorf("failed to get latest release: %w", err)
+		return newFetchError(CodeWASMRelease, fmt.Errorf("failed to get latest release: %w", err))
 	}
 
 	assets := map[*github.ReleaseAsset]struct{}{}
@@ -60,11 +60,11 @@ func (s *Scrapper) verifyRelease(ctx context.Context, repository *github.Reposit
 	}
 
 	if len(assets) > 1 {
-		return fmt.Errorf("too many zip archive (%d)", len(assets))
+		return newError(CodeWASMRelease, fmt.Errorf("too many zip archive (%d)", len(assets)))
 	}
 
 	if len(assets) == 0 {
-		return errors.New("zip archive not found")
+		return newError(CodeWASMRelease, errors.New("zip archive not found"))
 	}
 
 	for asset := range assets {
@@ -80,22 +80,22 @@ func (s *Scrapper) verifyRelease(ctx context.Context, repository *github.Reposit
 func (s *Scrapper) verifyZip(ctx context.Context, owner, repo string, assetID int64, manifest Manifest) error {
 	asset, _, err := s.gh.Repositories.DownloadReleaseAsset(ctx, owner, repo, assetID, s.gh.Client())
 	if err != nil {
-		return fmt.Errorf("failed to download asset: %w", err)
+		return newFetchError(CodeWASMRelease, fmt.Errorf("failed to download asset: %w", err))
 	}
 
 	body, err := io.ReadAll(asset)
 	if err != nil {
-		return fmt.Errorf("failed to read asset body: %w", err)
+		return newFetchError(CodeWASMRelease, fmt.Errorf("failed to read asset body: %w", err))
 	}
 
 	reader, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
 	if err != nil {
-		return fmt.Errorf("failed to unzip archive: %w", err)
+		return newError(CodeWASMArchive, fmt.Errorf("failed to unzip archive: %w", err))
 	}
 
 	wasmPath, err := getWasmPath(manifest)
 	if err != nil {
-		return err
+		return newError(CodeManifestInvalid, err)
 	}
 
 	var foundManifest bool
@@ -115,11 +115,11 @@ func (s *Scrapper) verifyZip(ctx context.Context, owner, repo string, assetID in
 	}
 
 	if wasmPluginFile == nil {
-		return errors.New("failed to find " + wasmPath)
+		return newError(CodeWASMArchive, errors.New("failed to find "+wasmPath))
 	}
 
 	if !foundManifest {
-		return errors.New("failed to find " + manifestFile)
+		return newError(CodeWASMArchive, errors.New("failed to find "+manifestFile))
 	}
 
 	switch manifest.Type {
@@ -128,7 +128,7 @@ func (s *Scrapper) verifyZip(ctx context.Context, owner, repo string, assetID in
 			return checkWasmMiddleware(ctx, wasmPluginFile, manifest)
 		})
 		if err != nil {
-			return fmt.Errorf("failed to check wasm middleware: %w", err)
+			return newError(CodeWASMRun, fmt.Errorf("failed to check wasm middleware: %w", err))
 		}
 
 	case typeProvider:
@@ -136,7 +136,7 @@ func (s *Scrapper) verifyZip(ctx context.Context, owner, repo string, assetID in
 		return nil
 
 	default:
-		return fmt.Errorf("unsupported type: %s", manifest.Type)
+		return newError(CodeManifestUnsupportedType, fmt.Errorf("unsupported type: %s", manifest.Type))
 	}
 
 	return nil
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"time"
@@ -19,43 +20,50 @@ import (
 	"golang.org/x/mod/module"
 )
 
-func (s *Scrapper) verifyYaegiPlugin(ctx context.Context, repository *github.Repository, latestVersion string, manifest Manifest) (string, []string, error) {
+// yaegiResult the outcome of the Yaegi check of a plugin.
+type yaegiResult struct {
+	warnings []Warning
+	// configSchema the JSON schema of the plugin configuration, nil if unknown.
+	configSchema map[string]interface{}
+}
+
+func (s *Scrapper) verifyYaegiPlugin(ctx context.Context, repository *github.Repository, latestVersion string, manifest Manifest) (string, []string, yaegiResult, error) {
 	// Gets module information
 	mod, err := s.getModuleInfo(ctx, repository, latestVersion)
 	if err != nil {
-		return "", nil, err
+		return "", nil, yaegiResult{}, err
 	}
 
 	pluginName := mod.Module.Mod.Path
 
 	// skip already existing plugin
 	prev, err := s.pg.GetByName(ctx, pluginName)
-	if err == nil && prev != nil && prev.LatestVersion == latestVersion && prev.Stars == repository.GetStargazersCount() {
-		return "", nil, nil
+	if err == nil && isUnchanged(prev, repository, latestVersion) {
+		return "", nil, yaegiResult{}, nil
 	}
 
 	// Checks module information
 	err = checkModuleFile(mod, manifest)
 	if err != nil {
-		return "", nil, err
+		return "", nil, yaegiResult{}, err
 	}
 
-	err = checkRepoName(repository, pluginName, manifest)
+	err = checkRepoName(repository, s.moduleHost, pluginName, manifest)
 	if err != nil {
-		return "", nil, err
+		return "", nil, yaegiResult{}, err
 	}
 
 	// Get versions
 	versions, err := s.getVersions(ctx, repository, pluginName)
 	if err != nil {
-		return "", nil, err
+		return "", nil, yaegiResult{}, err
 	}
 
 	// Creates temp GOPATH
 	var gop string
 	gop, err = os.MkdirTemp("", "traefik-plugin-gop")
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to create temp GOPATH: %w", err)
+		return "", nil, yaegiResult{}, newError(CodeInternal, fmt.Errorf("failed to create temp GOPATH: %w", err))
 	}
 
 	defer func() { _ = os.RemoveAll(gop) }()
@@ -63,22 +71,22 @@ func (s *Scrapper) verifyYaegiPlugin(ctx context.Context, repository *github.Rep
 	// Get sources
 	err = s.sources.Get(ctx, repository, gop, module.Version{Path: pluginName, Version: latestVersion})
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get sources: %w", err)
+		return "", nil, yaegiResult{}, newFetchError(CodeSourcesUnavailable, fmt.Errorf("failed to get sources: %w", err))
 	}
 
 	// Check Yaegi interface
-	err = s.yaegiCheck(manifest, gop, pluginName)
+	result, err := s.yaegiCheck(manifest, gop, pluginName)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to run the plugin with Yaegi: %w", err)
+		return "", nil, yaegiResult{}, fmt.Errorf("failed to run the plugin with Yaegi: %w", err)
 	}
 
-	return pluginName, versions, nil
+	return pluginName, versions, result, nil
 }
 
-func (s *Scrapper) yaegiCheck(manifest Manifest, goPath, moduleName string) (err error) {
+func (s *Scrapper) yaegiCheck(manifest Manifest, goPath, moduleName string) (_ yaegiResult, err error) {
 	defer func() {
 		if rec := recover(); rec != nil {
-			err = fmt.Errorf("panic from yaegi: %v", rec)
+			err = newError(CodeYaegiLoad, fmt.Errorf("panic from yaegi: %v", rec))
 		}
 	}()
 
@@ -89,17 +97,17 @@ func (s *Scrapper) yaegiCheck(manifest Manifest, goPath, moduleName string) (err
 	case typeMiddleware:
 		if manifest.UseUnsafe {
 			// Skip unsafe test
-			return nil
+			return yaegiResult{}, nil
 		}
 		_, skip := s.skipNewCall[moduleName]
-		return yaegiMiddlewareCheck(goPath, manifest, skip)
+		return yaegiMiddlewareCheck(goPath, manifest, skip, s.configDecoding)
 
 	case typeProvider:
 		// TODO yaegi check for provider
-		return nil
+		return yaegiResult{}, nil
 
 	default:
-		return fmt.Errorf("unsupported type: %s", manifest.Type)
+		return yaegiResult{}, newError(CodeManifestUnsupportedType, fmt.Errorf("unsupported type: %s", manifest.Type))
 	}
 }
 
@@ -112,30 +120,30 @@ func (s *Scrapper) getModuleInfo(ctx context.Context, repository *github.Reposit
 	contents, _, resp, err := s.gh.Repositories.GetContents(ctx, repository.GetOwner().GetLogin(), repository.GetName(), "go.mod", opts)
 	if resp != nil && resp.StatusCode == http.StatusNotFound {
 		span.RecordError(fmt.Errorf("missing manifest: %w", err))
-		return nil, fmt.Errorf("missing manifest: %w", err)
+		return nil, newError(CodeModuleMissing, fmt.Errorf("missing manifest: %w", err))
 	}
 
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, newFetchError(CodeModuleMissing, err)
 	}
 
 	content, err := contents.GetContent()
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, newError(CodeModuleInvalid, err)
 	}
 
 	mod, err := modfile.Parse("go.mod", []byte(content), nil)
 	if err != nil {
 		span.RecordError(err)
-		return nil, err
+		return nil, newError(CodeModuleInvalid, err)
 	}
 
 	return mod, nil
 }
 
-func yaegiMiddlewareCheck(goPath string, manifest Manifest, skipNew bool) error {
+func yaegiMiddlewareCheck(goPath string, manifest Manifest, skipNew bool, decoding string) (yaegiResult, error) {
 	middlewareName := "test"
 
 	next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {})
@@ -146,12 +154,12 @@ func yaegiMiddlewareCheck(goPath string, manifest Manifest, skipNew bool) error
 
 	i := interp.New(interp.Options{GoPath: goPath})
 	if err := i.Use(stdlib.Symbols); err != nil {
-		return fmt.Errorf("load of stdlib symbols: %w", err)
+		return yaegiResult{}, newError(CodeInternal, fmt.Errorf("load of stdlib symbols: %w", err))
 	}
 
 	_, err := i.EvalWithContext(ctx, fmt.Sprintf(`import %q`, manifest.Import))
 	if err != nil {
-		return fmt.Errorf("the load of the plugin takes too much time(%s), or an error, inside the plugin, occurs during the load: %w", timeout, err)
+		return yaegiResult{}, newError(CodeYaegiLoad, fmt.Errorf("the load of the plugin takes too much time(%s), or an error, inside the plugin, occurs during the load: %w", timeout, err))
 	}
 
 	basePkg := manifest.BasePkg
@@ -160,31 +168,55 @@ func yaegiMiddlewareCheck(goPath string, manifest Manifest, skipNew bool) error
 		basePkg = strings.ReplaceAll(basePkg, "-", "_")
 	}
 
-	vConfig, err := i.EvalWithContext(ctx, basePkg+`.CreateConfig()`)
+	createConfig := func() (reflect.Value, error) {
+		vConfig, err := i.EvalWithContext(ctx, basePkg+`.CreateConfig()`)
+		if err != nil {
+			return reflect.Value{}, newError(CodeYaegiCreateConfig, fmt.Errorf("failed to eval `CreateConfig` function: %w", err))
+		}
+
+		return vConfig, nil
+	}
+
+	vConfig, err := createConfig()
 	if err != nil {
-		return fmt.Errorf("failed to eval `CreateConfig` function: %w", err)
+		return yaegiResult{}, err
 	}
 
+	// The schema is generated before the decoding of the test data: the values returned by CreateConfig are the defaults.
+	docs := parseConfigDocs(filepath.Join(goPath, "src", filepath.FromSlash(manifest.Import)))
+
+	var result yaegiResult
+	result.configSchema, result.warnings = safeConfigSchema(vConfig, docs)
+
 	err = decodeConfig(vConfig, manifest.TestData)
 	if err != nil {
-		return err
+		return yaegiResult{}, newError(CodeYaegiTestData, err)
+	}
+
+	if decoding == DecodingTraefik {
+		decodingWarnings, err := checkTraefikDecoding(manifest, vConfig, createConfig)
+		if err != nil {
+			return yaegiResult{}, err
+		}
+
+		result.warnings = append(result.warnings, decodingWarnings...)
 	}
 
 	fnNew, err := i.EvalWithContext(ctx, basePkg+`.New`)
 	if err != nil {
-		return fmt.Errorf("failed to eval `New` function: %w", err)
+		return yaegiResult{}, newError(CodeYaegiNewSignature, fmt.Errorf("failed to eval `New` function: %w", err))
 	}
 
 	err = checkFunctionNewSignature(fnNew, vConfig)
 	if err != nil {
-		return fmt.Errorf("the signature of the function `New` is invalid: %w", err)
+		return yaegiResult{}, newError(CodeYaegiNewSignature, fmt.Errorf("the signature of the function `New` is invalid: %w", err))
 	}
 
 	if !skipNew {
-		return callNew(ctx, next, vConfig, middlewareName, fnNew)
+		return result, callNew(ctx, next, vConfig, middlewareName, fnNew)
 	}
 
-	return nil
+	return result, nil
 }
 
 func callNew(ctx context.Context, next http.HandlerFunc, vConfig reflect.Value, middlewareName string, fnNew reflect.Value) error {
@@ -211,21 +243,25 @@ func callNew(ctx context.Context, next http.HandlerFunc, vConfig reflect.Value,
 
 	select {
 	case err := <-errCh:
-		return err
+		if err != nil {
+			return newError(CodeYaegiNewFailed, err)
+		}
+
+		return nil
 	case <-ctx.Done():
-		return fmt.Errorf("the function `New` has failed: %w", ctx.Err())
+		return newError(CodeYaegiNewFailed, fmt.Errorf("the function `New` has failed: %w", ctx.Err()))
 	}
 }
 
-func checkRepoName(repository *github.Repository, moduleName string, manifest Manifest) error {
-	repoName := path.Join("github.com", repository.GetFullName())
+func checkRepoName(repository *github.Repository, moduleHost, moduleName string, manifest Manifest) error {
+	repoName := path.Join(moduleHost, repository.GetFullName())
 
 	if !strings.HasPrefix(moduleName, repoName) {
-		return fmt.Errorf("unsupported plugin: the module name (%s) doesn't contain the GitHub repository name (%s)", moduleName, repoName)
+		return newError(CodeModuleInvalidName, fmt.Errorf("unsupported plugin: the module name (%s) doesn't contain the GitHub repository name (%s)", moduleName, repoName))
 	}
 
 	if !strings.HasPrefix(manifest.Import, repoName) {
-		return fmt.Errorf("unsupported plugin: the import name (%s) doesn't contain the GitHub repository name (%s)", manifest.Import, repoName)
+		return newError(CodeModuleInvalidName, fmt.Errorf("unsupported plugin: the import name (%s) doesn't contain the GitHub repository name (%s)", manifest.Import, repoName))
 	}
 
 	return nil
@@ -239,12 +275,12 @@ func checkModuleFile(mod *modfile.File, manifest Manifest) error {
 			strings.Contains(require.Mod.Path, "github.com/traefik/yaegi") ||
 			strings.Contains(require.Mod.Path, "github.com/traefik/traefik") ||
 			strings.Contains(require.Mod.Path, "github.com/traefik/mesh") {
-			return fmt.Errorf("a plugin cannot have a dependence to: %s", require.Mod.Path)
+			return newError(CodeModuleForbiddenDep, fmt.Errorf("a plugin cannot have a dependence to: %s", require.Mod.Path))
 		}
 	}
 
 	if !strings.HasPrefix(strings.ReplaceAll(manifest.Import, "-", "_"), strings.ReplaceAll(mod.Module.Mod.Path, "-", "_")) {
-		return fmt.Errorf("the import %q must be related to the module name %q", manifest.Import, mod.Module.Mod.Path)
+		return newError(CodeModuleInvalidImport, fmt.Errorf("the import %q must be related to the module name %q", manifest.Import, mod.Module.Mod.Path))
 	}
 
 	return nil
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-github/v57/github"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
@@ -66,7 +67,7 @@ func TestUnsafe(t *testing.T) {
 			s := Scrapper{}
 			require.NoError(t, err)
 
-			err = s.yaegiCheck(manifest, tmpdir, "")
+			_, err = s.yaegiCheck(manifest, tmpdir, "")
 			if test.expectError {
 				require.Error(t, err)
 			} else {
@@ -76,6 +77,58 @@ func TestUnsafe(t *testing.T) {
 	}
 }
 
+func Test_checkRepoName(t *testing.T) {
+	repository := &github.Repository{FullName: github.String("traefik/plugintest")}
+
+	testCases := []struct {
+		desc        string
+		moduleHost  string
+		moduleName  string
+		importName  string
+		expectedErr string
+	}{
+		{
+			desc:       "github.com",
+			moduleHost: "github.com",
+			moduleName: "github.com/traefik/plugintest",
+			importName: "github.com/traefik/plugintest/example",
+		},
+		{
+			desc:       "enterprise",
+			moduleHost: "github.example.com",
+			moduleName: "github.example.com/traefik/plugintest",
+			importName: "github.example.com/traefik/plugintest",
+		},
+		{
+			desc:        "wrong module host",
+			moduleHost:  "github.example.com",
+			moduleName:  "github.com/traefik/plugintest",
+			importName:  "github.com/traefik/plugintest",
+			expectedErr: "unsupported plugin: the module name (github.com/traefik/plugintest) doesn't contain the GitHub repository name (github.example.com/traefik/plugintest)",
+		},
+		{
+			desc:        "wrong import",
+			moduleHost:  "github.com",
+			moduleName:  "github.com/traefik/plugintest",
+			importName:  "github.com/traefik/other",
+			expectedErr: "unsupported plugin: the import name (github.com/traefik/other) doesn't contain the GitHub repository name (github.com/traefik/plugintest)",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkRepoName(repository, test.moduleHost, test.moduleName, Manifest{Import: test.importName})
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 type LocalSources struct {
 	src string
 }
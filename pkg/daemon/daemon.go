@@ -0,0 +1,218 @@
+// Package daemon runs the scrapper periodically.
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+// Job a scrapper run.
+type Job func(ctx context.Context) (core.Stats, error)
+
+// Run the result of a run.
+type Run struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Stats    core.Stats    `json:"stats"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Status the status of the daemon.
+type Status struct {
+	Running bool      `json:"running"`
+	Started bool      `json:"started"`
+	NextRun time.Time `json:"nextRun"`
+	LastRun *Run      `json:"lastRun,omitempty"`
+}
+
+// Config the daemon configuration.
+type Config struct {
+	// Interval between two runs (ignored if Cron is set).
+	Interval time.Duration
+	// Cron expression (ex: "0 */2 * * *").
+	Cron string
+	// Jitter the maximum random delay before the first run.
+	Jitter time.Duration
+	// ShutdownTimeout the maximum time to wait for the current run on shutdown.
+	ShutdownTimeout time.Duration
+}
+
+// Daemon runs a job periodically, a run is skipped if the previous one is still in progress.
+type Daemon struct {
+	job             Job
+	schedule        cron.Schedule
+	jitter          time.Duration
+	shutdownTimeout time.Duration
+
+	// running is locked during a run.
+	running sync.Mutex
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New creates a new Daemon.
+func New(cfg Config, job Job) (*Daemon, error) {
+	var schedule cron.Schedule
+
+	switch {
+	case cfg.Cron != "":
+		var err error
+		schedule, err = cron.ParseStandard(cfg.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression: %w", err)
+		}
+
+	case cfg.Interval > 0:
+		schedule = cron.Every(cfg.Interval)
+
+	default:
+		return nil, errors.New("an interval or a cron expression is required")
+	}
+
+	return &Daemon{
+		job:             job,
+		schedule:        schedule,
+		jitter:          cfg.Jitter,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}, nil
+}
+
+// Run runs the job on schedule until the context is canceled.
+// On shutdown, the current run is awaited until the shutdown timeout, then canceled.
+func (d *Daemon) Run(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	defer d.wait(ctx, cancel)
+
+	if d.jitter > 0 {
+		//nolint:gosec // no need for a secure random.
+		delay := rand.N(d.jitter)
+
+		log.Ctx(ctx).Info().Dur("delay", delay).Msg("Delaying the first run")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	d.setStarted()
+
+	next := time.Now()
+
+	for {
+		d.setNextRun(next)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.runOnce(runCtx)
+		}()
+
+		next = d.schedule.Next(time.Now())
+	}
+}
+
+// Status returns the status of the daemon.
+func (d *Daemon) Status() Status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status := d.status
+	if status.LastRun != nil {
+		lastRun := *status.LastRun
+		status.LastRun = &lastRun
+	}
+
+	return status
+}
+
+func (d *Daemon) runOnce(ctx context.Context) {
+	if !d.running.TryLock() {
+		log.Ctx(ctx).Warn().Msg("The previous run is still in progress, skipping this run")
+		return
+	}
+
+	defer d.running.Unlock()
+
+	d.setRunning(true)
+	defer d.setRunning(false)
+
+	logger := log.Ctx(ctx)
+
+	run := &Run{Start: time.Now()}
+
+	logger.Info().Msg("Run started")
+
+	stats, err := d.job(ctx)
+
+	run.Duration = time.Since(run.Start)
+	run.Stats = stats
+
+	if err != nil {
+		run.Error = err.Error()
+		logger.Error().Err(err).Dur("duration", run.Duration).Msg("Run failed")
+	} else {
+		logger.Info().Dur("duration", run.Duration).Interface("stats", stats).Msg("Run completed")
+	}
+
+	d.mu.Lock()
+	d.status.LastRun = run
+	d.mu.Unlock()
+}
+
+func (d *Daemon) wait(ctx context.Context, cancel context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(d.shutdownTimeout):
+		log.Ctx(ctx).Warn().Msg("Shutdown timeout reached, canceling the current run")
+		cancel()
+		<-done
+	}
+}
+
+func (d *Daemon) setStarted() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.Started = true
+}
+
+func (d *Daemon) setRunning(running bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.Running = running
+}
+
+func (d *Daemon) setNextRun(next time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.status.NextRun = next
+}
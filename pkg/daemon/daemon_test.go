@@ -0,0 +1,172 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		cfg         Config
+		expectError bool
+	}{
+		{
+			desc: "interval",
+			cfg:  Config{Interval: time.Hour},
+		},
+		{
+			desc: "cron",
+			cfg:  Config{Cron: "0 */2 * * *"},
+		},
+		{
+			desc:        "invalid cron",
+			cfg:         Config{Cron: "every day"},
+			expectError: true,
+		},
+		{
+			desc:        "no schedule",
+			cfg:         Config{},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(test.cfg, nil)
+			if test.expectError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDaemon_Run(t *testing.T) {
+	var calls atomic.Int32
+
+	d, err := New(Config{Interval: time.Hour, ShutdownTimeout: time.Second}, func(_ context.Context) (core.Stats, error) {
+		calls.Add(1)
+		return core.Stats{Repositories: 2, Created: 1, Unchanged: 1}, nil
+	})
+	require.NoError(t, err)
+
+	server := httptest.NewServer(d.Handler())
+	t.Cleanup(server.Close)
+
+	assertStatusCode(t, server.URL+"/healthz", http.StatusOK)
+	assertStatusCode(t, server.URL+"/readyz", http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		return d.Status().LastRun != nil
+	}, time.Second, 10*time.Millisecond)
+
+	assertStatusCode(t, server.URL+"/readyz", http.StatusOK)
+	assertStatusCode(t, server.URL+"/", http.StatusOK)
+
+	resp, err := http.Get(server.URL + "/status")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	var status Status
+	err = json.NewDecoder(resp.Body).Decode(&status)
+	require.NoError(t, err)
+
+	assert.True(t, status.Started)
+	assert.Equal(t, core.Stats{Repositories: 2, Created: 1, Unchanged: 1}, status.LastRun.Stats)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), status.NextRun, time.Minute)
+
+	cancel()
+	<-done
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestDaemon_Run_failure(t *testing.T) {
+	d, err := New(Config{Interval: time.Hour, ShutdownTimeout: time.Second}, func(_ context.Context) (core.Stats, error) {
+		return core.Stats{}, errors.New("boom")
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go d.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		return d.Status().LastRun != nil
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "boom", d.Status().LastRun.Error)
+
+	server := httptest.NewServer(d.Handler())
+	t.Cleanup(server.Close)
+
+	// A failed run doesn't change the readiness.
+	assertStatusCode(t, server.URL+"/readyz", http.StatusOK)
+}
+
+func TestDaemon_runOnce_overlap(t *testing.T) {
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+
+	d, err := New(Config{Interval: time.Hour}, func(_ context.Context) (core.Stats, error) {
+		calls.Add(1)
+		<-release
+
+		return core.Stats{}, nil
+	})
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.runOnce(context.Background())
+	}()
+
+	require.Eventually(t, func() bool {
+		return d.Status().Running
+	}, time.Second, 10*time.Millisecond)
+
+	// Skipped: the previous run is still in progress.
+	d.runOnce(context.Background())
+
+	close(release)
+	<-done
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.False(t, d.Status().Running)
+}
+
+func assertStatusCode(t *testing.T, uri string, expected int) {
+	t.Helper()
+
+	resp, err := http.Get(uri)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	assert.Equal(t, expected, resp.StatusCode)
+}
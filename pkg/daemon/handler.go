@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+var statusPage = template.Must(template.New("status").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Piceus</title></head>
+<body>
+<h1>Piceus</h1>
+<p>Running: {{ .Running }}</p>
+<p>Next run: {{ if .NextRun.IsZero }}-{{ else }}{{ .NextRun.Format "2006-01-02T15:04:05Z07:00" }}{{ end }}</p>
+<h2>Last run</h2>
+{{ with .LastRun }}
+<table>
+<tr><th>Start</th><td>{{ .Start.Format "2006-01-02T15:04:05Z07:00" }}</td></tr>
+<tr><th>Duration</th><td>{{ .Duration }}</td></tr>
+<tr><th>Repositories</th><td>{{ .Stats.Repositories }}</td></tr>
+<tr><th>Created</th><td>{{ .Stats.Created }}</td></tr>
+<tr><th>Updated</th><td>{{ .Stats.Updated }}</td></tr>
+<tr><th>Unchanged</th><td>{{ .Stats.Unchanged }}</td></tr>
+<tr><th>Skipped</th><td>{{ .Stats.Skipped }}</td></tr>
+<tr><th>Failed</th><td>{{ .Stats.Failed }}</td></tr>
+<tr><th>Errors</th><td>{{ .Stats.Errors }}</td></tr>
+{{ with .Error }}<tr><th>Error</th><td>{{ . }}</td></tr>{{ end }}
+</table>
+{{ else }}
+<p>No run yet.</p>
+{{ end }}
+</body>
+</html>
+`))
+
+// Handler returns the health, readiness and status endpoints.
+//
+//	/healthz: the process is alive.
+//	/readyz: the daemon is started, the endpoints can serve (a failed run doesn't change the readiness).
+//	/status: the status (JSON), including the error of the last run.
+//	/: the status page (HTML).
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("GET /readyz", func(rw http.ResponseWriter, _ *http.Request) {
+		status := d.Status()
+
+		// The failed runs are reported by /status: removing the pod from the service would also take down the admin API and the webhooks.
+		if !status.Started {
+			http.Error(rw, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = rw.Write([]byte("ok"))
+	})
+
+	mux.HandleFunc("GET /status", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(rw).Encode(d.Status()); err != nil {
+			log.Error().Err(err).Msg("Failed to write the status")
+		}
+	})
+
+	mux.HandleFunc("GET /{$}", func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		if err := statusPage.Execute(rw, d.Status()); err != nil {
+			log.Error().Err(err).Msg("Failed to write the status page")
+		}
+	})
+
+	return mux
+}
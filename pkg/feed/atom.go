@@ -0,0 +1,94 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Updated  string      `xml:"updated"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Summary  string      `xml:"summary,omitempty"`
+	Content  *atomText   `xml:"content,omitempty"`
+	Category []atomTerm  `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomTerm struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom generates an Atom feed (RFC 4287).
+func Atom(events []Event, meta Metadata) ([]byte, error) {
+	feed := atomFeed{
+		ID:      meta.FeedURL,
+		Title:   meta.Title,
+		Updated: updated(events).Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: meta.Link},
+			{Href: strings.TrimSuffix(meta.FeedURL, "/") + "/atom.xml", Rel: "self"},
+		},
+	}
+
+	for _, event := range events {
+		entry := atomEntry{
+			ID:       "urn:piceus:" + event.ID(),
+			Title:    event.title(),
+			Updated:  event.Date.Format(time.RFC3339),
+			Summary:  event.Summary,
+			Category: []atomTerm{{Term: event.Type}},
+		}
+
+		if event.Author != "" {
+			entry.Author = &atomAuthor{Name: event.Author}
+		}
+
+		if event.URL != "" {
+			entry.Links = append(entry.Links, atomLink{Href: event.URL})
+		}
+
+		if event.IconURL != "" {
+			entry.Content = &atomText{
+				Type: "html",
+				Body: fmt.Sprintf(`<img src="%s" alt="icon"/><p>%s</p>`, html.EscapeString(event.IconURL), html.EscapeString(event.Summary)),
+			}
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Atom feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,189 @@
+// Package feed records the catalog changes and generates Atom and JSON feeds.
+package feed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	EventAdded    = "added"
+	EventReleased = "released"
+)
+
+// Event a catalog change.
+type Event struct {
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Summary     string    `json:"summary,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Version     string    `json:"version"`
+	IconURL     string    `json:"iconUrl,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Date        time.Time `json:"date"`
+}
+
+// ID returns a unique ID of the event.
+func (e Event) ID() string {
+	return e.Name + "@" + e.Version
+}
+
+func (e Event) title() string {
+	name := e.DisplayName
+	if name == "" {
+		name = e.Name
+	}
+
+	if e.Type == EventAdded {
+		return fmt.Sprintf("New plugin: %s %s", name, e.Version)
+	}
+
+	return fmt.Sprintf("%s %s released", name, e.Version)
+}
+
+// Metadata the feed metadata.
+type Metadata struct {
+	Title string
+	// Link the URL of the catalog.
+	Link string
+	// FeedURL the public URL of the directory containing the feed files.
+	FeedURL string
+}
+
+// Log an event log persisted into a JSON file.
+// Only the most recent events are kept.
+type Log struct {
+	filename  string
+	maxEvents int
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewLog creates a Log and loads the existing events.
+func NewLog(filename string, maxEvents int) (*Log, error) {
+	l := &Log{filename: filename, maxEvents: maxEvents}
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &l.events); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal events: %w", err)
+	}
+
+	return l, nil
+}
+
+// Record records an event.
+func (l *Log) Record(event Event) {
+	if event.Date.IsZero() {
+		event.Date = time.Now().UTC()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = slices.DeleteFunc(l.events, func(e Event) bool {
+		return e.ID() == event.ID()
+	})
+
+	l.events = append([]Event{event}, l.events...)
+
+	slices.SortStableFunc(l.events, func(a, b Event) int {
+		return b.Date.Compare(a.Date)
+	})
+
+	if l.maxEvents > 0 && len(l.events) > l.maxEvents {
+		l.events = l.events[:l.maxEvents]
+	}
+}
+
+// Events returns the events sorted from the most recent to the oldest.
+func (l *Log) Events() []Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.events)
+}
+
+// Save writes the events file.
+func (l *Log) Save() error {
+	data, err := json.MarshalIndent(l.Events(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal events: %w", err)
+	}
+
+	return writeFile(l.filename, data)
+}
+
+// WriteFeeds writes the event log, the Atom feed (atom.xml) and the JSON feed (feed.json) into a directory.
+func (l *Log) WriteFeeds(dir string, meta Metadata) error {
+	err := l.Save()
+	if err != nil {
+		return err
+	}
+
+	events := l.Events()
+
+	atom, err := Atom(events, meta)
+	if err != nil {
+		return err
+	}
+
+	err = writeFile(filepath.Join(dir, "atom.xml"), atom)
+	if err != nil {
+		return err
+	}
+
+	jsonFeed, err := JSONFeed(events, meta)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(dir, "feed.json"), jsonFeed)
+}
+
+func updated(events []Event) time.Time {
+	if len(events) == 0 {
+		return time.Unix(0, 0).UTC()
+	}
+
+	return events[0].Date
+}
+
+func writeFile(filename string, data []byte) error {
+	err := os.MkdirAll(filepath.Dir(filename), 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".feed-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	_, err = tmp.Write(data)
+	if cErr := tmp.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
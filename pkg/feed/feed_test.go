@@ -0,0 +1,95 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog_Record(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "events.json")
+
+	l, err := NewLog(filename, 2)
+	require.NoError(t, err)
+
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	l.Record(Event{Type: EventAdded, Name: "github.com/traefik/a", Version: "v0.1.0", Date: date})
+	l.Record(Event{Type: EventAdded, Name: "github.com/traefik/b", Version: "v0.1.0", Date: date.Add(time.Hour)})
+	l.Record(Event{Type: EventReleased, Name: "github.com/traefik/a", Version: "v0.2.0", Date: date.Add(2 * time.Hour)})
+	// Duplicated events are replaced.
+	l.Record(Event{Type: EventReleased, Name: "github.com/traefik/a", Version: "v0.2.0", Date: date.Add(3 * time.Hour)})
+
+	events := l.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "github.com/traefik/a@v0.2.0", events[0].ID())
+	assert.Equal(t, date.Add(3*time.Hour), events[0].Date)
+	assert.Equal(t, "github.com/traefik/b@v0.1.0", events[1].ID())
+
+	err = l.Save()
+	require.NoError(t, err)
+
+	reloaded, err := NewLog(filename, 2)
+	require.NoError(t, err)
+	assert.Equal(t, events, reloaded.Events())
+}
+
+func TestLog_WriteFeeds(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := NewLog(filepath.Join(dir, "events.json"), 10)
+	require.NoError(t, err)
+
+	l.Record(Event{
+		Type:        EventAdded,
+		Name:        "github.com/traefik/plugintest",
+		DisplayName: "Plugin Test",
+		Summary:     "A test plugin.",
+		Author:      "traefik",
+		Version:     "v0.1.0",
+		IconURL:     "https://raw.githubusercontent.com/traefik/plugintest/v0.1.0/icon.png",
+		URL:         "https://github.com/traefik/plugintest",
+		Date:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	meta := Metadata{Title: "Catalog", Link: "https://plugins.example.com", FeedURL: "https://plugins.example.com/feeds"}
+
+	err = l.WriteFeeds(dir, meta)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "atom.xml"))
+	require.NoError(t, err)
+
+	var atom atomFeed
+	err = xml.Unmarshal(data, &atom)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Catalog", atom.Title)
+	assert.Equal(t, "2024-01-01T00:00:00Z", atom.Updated)
+	require.Len(t, atom.Entries, 1)
+	assert.Equal(t, "New plugin: Plugin Test v0.1.0", atom.Entries[0].Title)
+	assert.Equal(t, "A test plugin.", atom.Entries[0].Summary)
+	assert.Equal(t, "traefik", atom.Entries[0].Author.Name)
+	assert.Contains(t, atom.Entries[0].Content.Body, "icon.png")
+
+	data, err = os.ReadFile(filepath.Join(dir, "feed.json"))
+	require.NoError(t, err)
+
+	var jf jsonFeed
+	err = json.Unmarshal(data, &jf)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://plugins.example.com/feeds/feed.json", jf.FeedURL)
+	require.Len(t, jf.Items, 1)
+	assert.Equal(t, "github.com/traefik/plugintest@v0.1.0", jf.Items[0].ID)
+	assert.Equal(t, "https://raw.githubusercontent.com/traefik/plugintest/v0.1.0/icon.png", jf.Items[0].Image)
+	assert.Equal(t, []jsonAuthor{{Name: "traefik"}}, jf.Items[0].Authors)
+
+	assert.FileExists(t, filepath.Join(dir, "events.json"))
+}
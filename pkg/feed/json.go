@@ -0,0 +1,69 @@
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type jsonFeed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	HomePageURL string     `json:"home_page_url,omitempty"`
+	FeedURL     string     `json:"feed_url,omitempty"`
+	Items       []jsonItem `json:"items"`
+}
+
+type jsonItem struct {
+	ID            string       `json:"id"`
+	URL           string       `json:"url,omitempty"`
+	Title         string       `json:"title"`
+	ContentText   string       `json:"content_text"`
+	Summary       string       `json:"summary,omitempty"`
+	Image         string       `json:"image,omitempty"`
+	DatePublished string       `json:"date_published"`
+	Authors       []jsonAuthor `json:"authors,omitempty"`
+	Tags          []string     `json:"tags,omitempty"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSONFeed generates a JSON Feed (version 1.1).
+func JSONFeed(events []Event, meta Metadata) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       meta.Title,
+		HomePageURL: meta.Link,
+		FeedURL:     strings.TrimSuffix(meta.FeedURL, "/") + "/feed.json",
+		Items:       []jsonItem{},
+	}
+
+	for _, event := range events {
+		item := jsonItem{
+			ID:            event.ID(),
+			URL:           event.URL,
+			Title:         event.title(),
+			ContentText:   event.Summary,
+			Summary:       event.Summary,
+			Image:         event.IconURL,
+			DatePublished: event.Date.Format(time.RFC3339),
+			Tags:          []string{event.Type},
+		}
+
+		if event.Author != "" {
+			item.Authors = []jsonAuthor{{Name: event.Author}}
+		}
+
+		feed.Items = append(feed.Items, item)
+	}
+
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON feed: %w", err)
+	}
+
+	return data, nil
+}
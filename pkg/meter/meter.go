@@ -4,11 +4,17 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/embedded"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -16,8 +22,19 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
+// Metrics exporters.
+const (
+	ExporterOTLPHTTP   = "otlp-http"
+	ExporterOTLPGRPC   = "otlp-grpc"
+	ExporterStdout     = "stdout"
+	ExporterPrometheus = "prometheus"
+	ExporterNone       = "none"
+)
+
 // Config holds the metrics configuration.
 type Config struct {
+	// Exporter the metrics exporter (otlp-http, otlp-grpc, stdout, prometheus, none).
+	Exporter    string
 	Address     string
 	Insecure    bool
 	Username    string
@@ -25,59 +42,88 @@ type Config struct {
 	ServiceName string
 }
 
-// OTLPProvider is a metrics provider which exports metrics to OTLP.
-type OTLPProvider struct {
+// Provider is a metrics provider which exports metrics with the configured exporter.
+type Provider struct {
 	embedded.MeterProvider
 
 	provider *sdkmetric.MeterProvider
-	reader   sdkmetric.Reader
-	exporter sdkmetric.Exporter
+	handler  http.Handler
 }
 
-// NewOTLPProvider creates a new OTLPProvider.
-func NewOTLPProvider(ctx context.Context, cfg Config) (*OTLPProvider, error) {
-	auth := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
-
-	options := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithEndpoint(cfg.Address),
-		otlpmetrichttp.WithHeaders(map[string]string{"Authorization": "Basic " + auth}),
+// NewProvider creates a new Provider.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	p := &Provider{}
+
+	options := []sdkmetric.Option{
+		sdkmetric.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+			// The exporter label is kept for the existing dashboards, it is not the configured exporter.
+			attribute.String("exporter", "prometheus"),
+			attribute.String("namespace", currentNamespace()),
+		)),
 	}
 
-	if cfg.Insecure {
-		options = append(options, otlpmetrichttp.WithInsecure())
-	}
+	switch cfg.Exporter {
+	case ExporterOTLPHTTP, "":
+		exporter, err := newOTLPHTTPExporter(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
 
-	exporter, err := otlpmetrichttp.New(ctx, options...)
-	if err != nil {
-		return nil, fmt.Errorf("create otlp exporter: %w", err)
+		options = append(options, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+
+	case ExporterOTLPGRPC:
+		exporter, err := newOTLPGRPCExporter(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+
+	case ExporterStdout:
+		exporter, err := stdoutmetric.New()
+		if err != nil {
+			return nil, fmt.Errorf("create stdout exporter: %w", err)
+		}
+
+		options = append(options, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+
+	case ExporterPrometheus:
+		registry := prometheus.NewRegistry()
+
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, fmt.Errorf("create prometheus exporter: %w", err)
+		}
+
+		options = append(options, sdkmetric.WithReader(exporter))
+		p.handler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+
+	case ExporterNone:
+		// The metrics are recorded but not exported.
+
+	default:
+		return nil, fmt.Errorf("unsupported metrics exporter: %s", cfg.Exporter)
 	}
 
-	reader := sdkmetric.NewManualReader()
-
-	return &OTLPProvider{
-		provider: sdkmetric.NewMeterProvider(
-			sdkmetric.WithResource(resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(cfg.ServiceName),
-				attribute.String("exporter", "prometheus"),
-				attribute.String("namespace", currentNamespace()),
-			)),
-			sdkmetric.WithReader(
-				sdkmetric.NewPeriodicReader(exporter),
-			),
-		),
-		exporter: exporter,
-		reader:   reader,
-	}, nil
+	p.provider = sdkmetric.NewMeterProvider(options...)
+
+	return p, nil
 }
 
 // Meter returns a Meter with the given name and configured with options.
-func (p *OTLPProvider) Meter(name string, options ...metric.MeterOption) metric.Meter {
+func (p *Provider) Meter(name string, options ...metric.MeterOption) metric.Meter {
 	return p.provider.Meter(name, options...)
 }
 
+// Handler returns the Prometheus /metrics handler, or nil if the exporter is not prometheus.
+func (p *Provider) Handler() http.Handler {
+	return p.handler
+}
+
 // Stop stops the provider once all metrics have been uploaded.
-func (p *OTLPProvider) Stop(ctx context.Context) error {
+func (p *Provider) Stop(ctx context.Context) error {
 	if err := p.provider.ForceFlush(ctx); err != nil {
 		return fmt.Errorf("flushing provider: %w", err)
 	}
@@ -89,6 +135,53 @@ func (p *OTLPProvider) Stop(ctx context.Context) error {
 	return nil
 }
 
+func newOTLPHTTPExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	options := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(cfg.Address),
+		otlpmetrichttp.WithHeaders(authHeaders(cfg)),
+	}
+
+	if cfg.Insecure {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+func newOTLPGRPCExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	options := []otlpmetricgrpc.Option{
+		otlpmetricgrpc.WithEndpoint(cfg.Address),
+		otlpmetricgrpc.WithHeaders(authHeaders(cfg)),
+	}
+
+	if cfg.Insecure {
+		options = append(options, otlpmetricgrpc.WithInsecure())
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp grpc exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// authHeaders returns the basic authentication header, if credentials are configured.
+func authHeaders(cfg Config) map[string]string {
+	if cfg.Username == "" && cfg.Password == "" {
+		return nil
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+
+	return map[string]string{"Authorization": "Basic " + auth}
+}
+
 func currentNamespace() string {
 	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
 		return ns
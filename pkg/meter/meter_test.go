@@ -0,0 +1,84 @@
+package meter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvider(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		exporter    string
+		wantHandler bool
+		expectError bool
+	}{
+		{
+			desc:     "otlp http",
+			exporter: ExporterOTLPHTTP,
+		},
+		{
+			desc:     "otlp grpc",
+			exporter: ExporterOTLPGRPC,
+		},
+		{
+			desc:     "stdout",
+			exporter: ExporterStdout,
+		},
+		{
+			desc:        "prometheus",
+			exporter:    ExporterPrometheus,
+			wantHandler: true,
+		},
+		{
+			desc:     "none",
+			exporter: ExporterNone,
+		},
+		{
+			desc:        "unknown",
+			exporter:    "statsd",
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := NewProvider(context.Background(), Config{Exporter: test.exporter, Address: "localhost:4318", Insecure: true})
+			if test.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, test.wantHandler, provider.Handler() != nil)
+		})
+	}
+}
+
+func TestProvider_Handler(t *testing.T) {
+	provider, err := NewProvider(context.Background(), Config{Exporter: ExporterPrometheus, ServiceName: "piceus"})
+	require.NoError(t, err)
+
+	counter, err := provider.Meter("piceus").Int64Counter("piceus.issues.opened")
+	require.NoError(t, err)
+
+	counter.Add(context.Background(), 2)
+
+	rw := httptest.NewRecorder()
+	provider.Handler().ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+
+	body, err := io.ReadAll(rw.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "piceus_issues_opened_total")
+}
@@ -32,7 +32,7 @@ func (s *GitHub) Get(ctx context.Context, repository *github.Repository, gop str
 
 	// Gets code (archive)
 
-	archivePath, err := s.getArchive(ctx, repository, mod.Version, rootArchive)
+	archivePath, err := s.getArchive(ctx, repository, mod.Path, mod.Version, rootArchive)
 
 	defer func() {
 		if archivePath != "" {
@@ -65,7 +65,7 @@ func (s *GitHub) Get(ctx context.Context, repository *github.Repository, gop str
 	return nil
 }
 
-func (s *GitHub) getArchive(ctx context.Context, repository *github.Repository, version, rootArchive string) (string, error) {
+func (s *GitHub) getArchive(ctx context.Context, repository *github.Repository, modulePath, version, rootArchive string) (string, error) {
 	opts := &github.RepositoryContentGetOptions{Ref: version}
 
 	link, _, err := s.Client.Repositories.GetArchiveLink(ctx, repository.GetOwner().GetLogin(), repository.GetName(), github.Zipball, opts, 3)
@@ -78,8 +78,7 @@ func (s *GitHub) getArchive(ctx context.Context, repository *github.Repository,
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
-	// TODO remove hardcoded github.com?
-	filename := filepath.Join(rootArchive, "github.com", filepath.FromSlash(repository.GetFullName()), version+".zip")
+	filename := filepath.Join(rootArchive, filepath.FromSlash(modulePath), version+".zip")
 
 	err = os.MkdirAll(filepath.Dir(filename), 0o750)
 	if err != nil {
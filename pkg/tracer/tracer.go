@@ -10,8 +10,9 @@ import (
 	"github.com/rs/zerolog/log"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -22,8 +23,18 @@ import (
 
 type traceIDSet struct{}
 
+// Tracing exporters.
+const (
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterStdout   = "stdout"
+	ExporterNone     = "none"
+)
+
 // Config holds the tracing configuration.
 type Config struct {
+	// Exporter the tracing exporter (otlp-http, otlp-grpc, stdout, none).
+	Exporter    string
 	Address     string
 	Insecure    bool
 	Username    string
@@ -32,15 +43,15 @@ type Config struct {
 	ServiceName string
 }
 
-// OTLP is an extension of otel tracer to add custom logging.
-type OTLP struct {
+// Tracer is an extension of otel tracer to add custom logging.
+type Tracer struct {
 	embedded.Tracer
 
 	tracer trace.Tracer
 }
 
 // Start returns a new span with the corresponding trace id field filled.
-func (t *OTLP) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+func (t *Tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	ctx, span := t.tracer.Start(ctx, spanName, opts...)
 
 	if !span.SpanContext().IsSampled() || ctx.Value(traceIDSet{}) != nil {
@@ -53,72 +64,129 @@ func (t *OTLP) Start(ctx context.Context, spanName string, opts ...trace.SpanSta
 	return logger.WithContext(ctx), span
 }
 
-// OTLPProvider is a trace provider which exports traces to OTLP.
-type OTLPProvider struct {
+// Provider is a trace provider which exports traces with the configured exporter.
+type Provider struct {
 	embedded.TracerProvider
 
 	provider *sdktrace.TracerProvider
-	exporter *otlptrace.Exporter
 }
 
-// NewOTLPProvider creates a new OTLPProvider.
-func NewOTLPProvider(ctx context.Context, cfg Config) (*OTLPProvider, error) {
-	auth := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+// NewProvider creates a new Provider.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Probability))
 
-	options := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(cfg.Address),
-		otlptracehttp.WithHeaders(map[string]string{"Authorization": "Basic " + auth}),
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+			// The exporter label is kept for the existing dashboards, it is not the configured exporter.
+			attribute.String("exporter", "jaeger"),
+			attribute.String("namespace", currentNamespace()),
+		)),
 	}
 
-	if cfg.Insecure {
-		options = append(options, otlptracehttp.WithInsecure())
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case ExporterOTLPHTTP, "":
+		exporter, err = newOTLPHTTPExporter(ctx, cfg)
+
+	case ExporterOTLPGRPC:
+		exporter, err = newOTLPGRPCExporter(ctx, cfg)
+
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+		if err != nil {
+			err = fmt.Errorf("create stdout exporter: %w", err)
+		}
+
+	case ExporterNone:
+		// The spans are created but not exported.
+
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter: %s", cfg.Exporter)
 	}
 
-	exporter, err := otlptracehttp.New(ctx, options...)
 	if err != nil {
-		return nil, fmt.Errorf("create otlp exporter: %w", err)
+		return nil, err
 	}
 
-	bsp := sdktrace.NewBatchSpanProcessor(exporter)
-	sampler := sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Probability))
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
 
 	tc := propagation.TraceContext{}
 	otel.SetTextMapPropagator(tc)
 
-	return &OTLPProvider{
-		provider: sdktrace.NewTracerProvider(
-			sdktrace.WithSampler(sampler),
-			sdktrace.WithResource(resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(cfg.ServiceName),
-				attribute.String("exporter", "jaeger"),
-				attribute.String("namespace", currentNamespace()),
-			)),
-			sdktrace.WithSpanProcessor(bsp),
-		),
-		exporter: exporter,
-	}, nil
+	return &Provider{provider: sdktrace.NewTracerProvider(options...)}, nil
 }
 
 // Tracer creates a new tracer with the given name and options.
-func (p *OTLPProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
-	return &OTLP{
+func (p *Provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &Tracer{
 		tracer: p.provider.Tracer(name, opts...),
 	}
 }
 
 // Stop stops the provider once all traces have been uploaded.
-func (p *OTLPProvider) Stop(ctx context.Context) error {
+// The exporter is shut down by the provider.
+func (p *Provider) Stop(ctx context.Context) error {
 	if err := p.provider.ForceFlush(ctx); err != nil {
 		return err
 	}
-	if err := p.exporter.Shutdown(ctx); err != nil {
-		return err
-	}
 
 	return p.provider.Shutdown(ctx)
 }
 
+func newOTLPHTTPExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Address),
+		otlptracehttp.WithHeaders(authHeaders(cfg)),
+	}
+
+	if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+func newOTLPGRPCExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	options := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Address),
+		otlptracegrpc.WithHeaders(authHeaders(cfg)),
+	}
+
+	if cfg.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp grpc exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+// authHeaders returns the basic authentication header, if credentials are configured.
+func authHeaders(cfg Config) map[string]string {
+	if cfg.Username == "" && cfg.Password == "" {
+		return nil
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
+
+	return map[string]string{"Authorization": "Basic " + auth}
+}
+
 func currentNamespace() string {
 	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
 		return ns
@@ -0,0 +1,135 @@
+package webhook
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+// Analyzer analyzes a single repository.
+type Analyzer interface {
+	RunRepository(ctx context.Context, owner, name string) (core.Result, error)
+	ReconcileRepository(ctx context.Context, owner, name string) (core.Result, error)
+}
+
+type job struct {
+	owner string
+	name  string
+	// reconcile reconciles the catalog entries of the repository instead of analyzing it.
+	reconcile bool
+	// reconcileUnmatched reconciles the catalog entries of the repository if it doesn't match the search queries anymore
+	// (ex: plugin topic removed).
+	reconcileUnmatched bool
+}
+
+func (j job) key() string {
+	key := strings.ToLower(j.owner + "/" + j.name)
+
+	switch {
+	case j.reconcile:
+		return "reconcile:" + key
+	case j.reconcileUnmatched:
+		return "reconcile-unmatched:" + key
+	default:
+		return key
+	}
+}
+
+// Queue a queue of repositories to analyze.
+// A repository already waiting in the queue is not queued twice.
+type Queue struct {
+	analyzer Analyzer
+	jobs     chan job
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+}
+
+// NewQueue creates a new Queue.
+func NewQueue(analyzer Analyzer, size int) *Queue {
+	return &Queue{
+		analyzer: analyzer,
+		jobs:     make(chan job, size),
+		pending:  make(map[string]struct{}),
+	}
+}
+
+// Push queues the analysis of a repository.
+// Returns false if the queue is full.
+func (q *Queue) Push(owner, name string) bool {
+	return q.push(job{owner: owner, name: name})
+}
+
+// PushReconcile queues the reconciliation of the catalog entries of a repository which cannot be analyzed anymore.
+// Returns false if the queue is full.
+func (q *Queue) PushReconcile(owner, name string) bool {
+	return q.push(job{owner: owner, name: name, reconcile: true})
+}
+
+func (q *Queue) push(j job) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.pending[j.key()]; ok {
+		return true
+	}
+
+	select {
+	case q.jobs <- j:
+		q.pending[j.key()] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// Run analyzes the queued repositories until the context is canceled.
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case j := <-q.jobs:
+			q.mu.Lock()
+			delete(q.pending, j.key())
+			q.mu.Unlock()
+
+			logger := log.Ctx(ctx).With().Str("repo_name", j.owner+"/"+j.name).Logger()
+			if j.reconcile {
+				q.reconcile(logger.WithContext(ctx), j)
+				continue
+			}
+
+			logger.Info().Msg("Analyzing repository")
+
+			result, err := q.analyzer.RunRepository(logger.WithContext(ctx), j.owner, j.name)
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to analyze repository")
+				continue
+			}
+
+			logger.Info().Str("outcome", result.Outcome).Str("skip_reason", result.SkipReason).Msg("Repository analyzed")
+
+			if j.reconcileUnmatched && result.SkipReason == core.SkipReasonNotMatching {
+				q.reconcile(logger.WithContext(ctx), j)
+			}
+		}
+	}
+}
+
+func (q *Queue) reconcile(ctx context.Context, j job) {
+	logger := log.Ctx(ctx)
+	logger.Info().Msg("Reconciling repository")
+
+	result, err := q.analyzer.ReconcileRepository(ctx, j.owner, j.name)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to reconcile repository")
+		return
+	}
+
+	logger.Info().Str("outcome", result.Outcome).Msg("Repository reconciled")
+}
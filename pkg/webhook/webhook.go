@@ -0,0 +1,143 @@
+// Package webhook receives the GitHub webhooks and queues the analysis of the related repositories.
+package webhook
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/google/go-github/v57/github"
+	"github.com/rs/zerolog/log"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+// Handler the GitHub webhook handler.
+type Handler struct {
+	secret []byte
+	queue  *Queue
+}
+
+// NewHandler creates a new Handler.
+// The payloads are verified with the X-Hub-Signature-256 header.
+func NewHandler(secret string, queue *Queue) *Handler {
+	return &Handler{secret: []byte(secret), queue: queue}
+}
+
+// ServeHTTP handles a GitHub webhook.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	logger := log.Ctx(req.Context()).With().
+		Str("event", github.WebHookType(req)).
+		Str("delivery", github.DeliveryID(req)).
+		Logger()
+
+	if len(h.secret) == 0 || req.Header.Get(github.SHA256SignatureHeader) == "" {
+		http.Error(rw, "missing signature", http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := github.ValidatePayload(req, h.secret)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Invalid webhook payload")
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	event, err := github.ParseWebHook(github.WebHookType(req), payload)
+	if err != nil {
+		logger.Debug().Err(err).Msg("Unsupported webhook event")
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	jobs := jobsOf(event)
+	if len(jobs) == 0 {
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	for _, j := range jobs {
+		if !h.queue.push(j) {
+			logger.Error().Str("repo_name", j.owner+"/"+j.name).Msg("Queue full, dropping the event")
+			http.Error(rw, "queue full", http.StatusServiceUnavailable)
+			return
+		}
+
+		logger.Debug().Str("repo_name", j.owner+"/"+j.name).Bool("reconcile", j.reconcile).Msg("Repository queued")
+	}
+
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// jobsOf returns the jobs required by an event, or nil if the event doesn't require an analysis.
+func jobsOf(event interface{}) []job {
+	switch e := event.(type) {
+	case *github.ReleaseEvent:
+		if e.GetAction() == "published" || e.GetAction() == "released" {
+			return []job{analyzeJob(e.GetRepo())}
+		}
+
+	case *github.CreateEvent:
+		if e.GetRefType() == "tag" {
+			return []job{analyzeJob(e.GetRepo())}
+		}
+
+	case *github.RepositoryEvent:
+		return repositoryJobs(e)
+
+	case *github.IssuesEvent:
+		// The analysis restarts when the analyzer issue is closed.
+		if e.GetAction() == "closed" && core.IsAnalyzerIssue(e.GetIssue().GetTitle()) {
+			return []job{analyzeJob(e.GetRepo())}
+		}
+	}
+
+	return nil
+}
+
+// repositoryJobs returns the jobs required by a repository event.
+// The archived, deleted and private repositories don't match the search queries anymore: their catalog entries are reconciled.
+// The edited repositories are analyzed, and reconciled if they don't match the search queries anymore (ex: plugin topic removed).
+// The renamed and transferred repositories are analyzed under their new name,
+// then the catalog entries still related to the old name are reconciled.
+func repositoryJobs(e *github.RepositoryEvent) []job {
+	repository := e.GetRepo()
+
+	switch e.GetAction() {
+	case "edited":
+		// Topic changes are "edited" events.
+		j := analyzeJob(repository)
+		j.reconcileUnmatched = true
+
+		return []job{j}
+
+	case "unarchived", "publicized":
+		return []job{analyzeJob(repository)}
+
+	case "archived", "deleted", "privatized":
+		return []job{{owner: repository.GetOwner().GetLogin(), name: repository.GetName(), reconcile: true}}
+
+	case "renamed":
+		jobs := []job{analyzeJob(repository)}
+
+		if from := e.GetChanges().GetRepo().GetName().GetFrom(); from != "" {
+			jobs = append(jobs, job{owner: repository.GetOwner().GetLogin(), name: from, reconcile: true})
+		}
+
+		return jobs
+
+	case "transferred":
+		jobs := []job{analyzeJob(repository)}
+
+		from := e.GetChanges().GetOwner().GetOwnerInfo()
+		if login := cmp.Or(from.GetUser().GetLogin(), from.GetOrg().GetLogin()); login != "" {
+			jobs = append(jobs, job{owner: login, name: repository.GetName(), reconcile: true})
+		}
+
+		return jobs
+	}
+
+	return nil
+}
+
+func analyzeJob(repository *github.Repository) job {
+	return job{owner: repository.GetOwner().GetLogin(), name: repository.GetName()}
+}
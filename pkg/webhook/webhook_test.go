@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/piceus/pkg/core"
+)
+
+const secret = "s3cr3t"
+
+type recordAnalyzer struct {
+	mu         sync.Mutex
+	repos      []string
+	reconciled []string
+}
+
+func (r *recordAnalyzer) RunRepository(_ context.Context, owner, name string) (core.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.repos = append(r.repos, owner+"/"+name)
+
+	if strings.HasPrefix(name, "unmatched") {
+		return core.Result{Repository: owner + "/" + name, Outcome: core.OutcomeSkipped, SkipReason: core.SkipReasonNotMatching}, nil
+	}
+
+	return core.Result{Repository: owner + "/" + name, Outcome: core.OutcomeUnchanged}, nil
+}
+
+func (r *recordAnalyzer) ReconcileRepository(_ context.Context, owner, name string) (core.Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reconciled = append(r.reconciled, owner+"/"+name)
+
+	return core.Result{Repository: owner + "/" + name, Outcome: core.OutcomeReconciled}, nil
+}
+
+func TestHandler(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		event      string
+		payload    string
+		signature  string
+		wantStatus int
+		wantJobs   []job
+	}{
+		{
+			desc:       "release published",
+			event:      "release",
+			payload:    `{"action":"published","release":{"tag_name":"v0.1.0"},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest"}},
+		},
+		{
+			desc:       "release deleted",
+			event:      "release",
+			payload:    `{"action":"deleted","release":{"tag_name":"v0.1.0"},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc:       "tag created",
+			event:      "create",
+			payload:    `{"ref":"v0.1.0","ref_type":"tag","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest"}},
+		},
+		{
+			desc:       "branch created",
+			event:      "create",
+			payload:    `{"ref":"feature","ref_type":"branch","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc:       "repository topics edited",
+			event:      "repository",
+			payload:    `{"action":"edited","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest", reconcileUnmatched: true}},
+		},
+		{
+			desc:       "repository archived",
+			event:      "repository",
+			payload:    `{"action":"archived","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest", reconcile: true}},
+		},
+		{
+			desc:       "repository deleted",
+			event:      "repository",
+			payload:    `{"action":"deleted","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest", reconcile: true}},
+		},
+		{
+			desc:       "repository made private",
+			event:      "repository",
+			payload:    `{"action":"privatized","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest", reconcile: true}},
+		},
+		{
+			desc:       "repository renamed",
+			event:      "repository",
+			payload:    `{"action":"renamed","changes":{"repository":{"name":{"from":"oldname"}}},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs: []job{
+				{owner: "traefik", name: "plugintest"},
+				{owner: "traefik", name: "oldname", reconcile: true},
+			},
+		},
+		{
+			desc:       "repository transferred",
+			event:      "repository",
+			payload:    `{"action":"transferred","changes":{"owner":{"from":{"user":{"login":"ldez"}}}},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs: []job{
+				{owner: "traefik", name: "plugintest"},
+				{owner: "ldez", name: "plugintest", reconcile: true},
+			},
+		},
+		{
+			desc:       "analyzer issue closed",
+			event:      "issues",
+			payload:    `{"action":"closed","issue":{"title":"[Traefik Plugin Catalog] Plugin Analyzer has detected a problem."},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusAccepted,
+			wantJobs:   []job{{owner: "traefik", name: "plugintest"}},
+		},
+		{
+			desc:       "other issue closed",
+			event:      "issues",
+			payload:    `{"action":"closed","issue":{"title":"Bug"},"repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc:       "ping",
+			event:      "ping",
+			payload:    `{"zen":"Keep it logically awesome."}`,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			desc:       "invalid signature",
+			event:      "release",
+			payload:    `{"action":"published","repository":{"name":"plugintest","full_name":"traefik/plugintest","owner":{"login":"traefik"}}}`,
+			signature:  "sha256=0000",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			queue := NewQueue(&recordAnalyzer{}, 10)
+			handler := NewHandler(secret, queue)
+
+			signature := test.signature
+			if signature == "" {
+				signature = sign(test.payload)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(test.payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-GitHub-Event", test.event)
+			req.Header.Set("X-Hub-Signature-256", signature)
+
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+
+			assert.Equal(t, test.wantStatus, rw.Code)
+
+			var jobs []job
+			for len(queue.jobs) > 0 {
+				jobs = append(jobs, <-queue.jobs)
+			}
+
+			assert.Equal(t, test.wantJobs, jobs)
+		})
+	}
+}
+
+func TestHandler_missingSignature(t *testing.T) {
+	handler := NewHandler(secret, NewQueue(&recordAnalyzer{}, 10))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "ping")
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+}
+
+func TestQueue(t *testing.T) {
+	analyzer := &recordAnalyzer{}
+	queue := NewQueue(analyzer, 2)
+
+	assert.True(t, queue.Push("traefik", "a"))
+	// Already queued.
+	assert.True(t, queue.Push("Traefik", "A"))
+	assert.True(t, queue.Push("traefik", "b"))
+	// Full.
+	assert.False(t, queue.Push("traefik", "c"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go queue.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		analyzer.mu.Lock()
+		defer analyzer.mu.Unlock()
+
+		return len(analyzer.repos) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	analyzer.mu.Lock()
+	defer analyzer.mu.Unlock()
+
+	assert.Equal(t, []string{"traefik/a", "traefik/b"}, analyzer.repos)
+}
+
+func TestQueue_reconcile(t *testing.T) {
+	analyzer := &recordAnalyzer{}
+	queue := NewQueue(analyzer, 3)
+
+	assert.True(t, queue.Push("traefik", "a"))
+	// The reconciliation is not the analysis.
+	assert.True(t, queue.PushReconcile("traefik", "a"))
+	// Already queued.
+	assert.True(t, queue.PushReconcile("Traefik", "A"))
+	assert.Len(t, queue.jobs, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go queue.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		analyzer.mu.Lock()
+		defer analyzer.mu.Unlock()
+
+		return len(analyzer.repos) == 1 && len(analyzer.reconciled) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	analyzer.mu.Lock()
+	defer analyzer.mu.Unlock()
+
+	assert.Equal(t, []string{"traefik/a"}, analyzer.repos)
+	assert.Equal(t, []string{"traefik/a"}, analyzer.reconciled)
+}
+
+func TestQueue_reconcileUnmatched(t *testing.T) {
+	analyzer := &recordAnalyzer{}
+	queue := NewQueue(analyzer, 3)
+
+	assert.True(t, queue.push(job{owner: "traefik", name: "matched", reconcileUnmatched: true}))
+	assert.True(t, queue.push(job{owner: "traefik", name: "unmatched", reconcileUnmatched: true}))
+	// Not reconciled without the flag.
+	assert.True(t, queue.Push("traefik", "unmatched-release"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	go queue.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		analyzer.mu.Lock()
+		defer analyzer.mu.Unlock()
+
+		return len(analyzer.repos) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	analyzer.mu.Lock()
+	defer analyzer.mu.Unlock()
+
+	assert.Equal(t, []string{"traefik/matched", "traefik/unmatched", "traefik/unmatched-release"}, analyzer.repos)
+	assert.Equal(t, []string{"traefik/unmatched"}, analyzer.reconciled)
+}
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}